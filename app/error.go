@@ -1,5 +1,12 @@
 package app
 
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+)
+
 // ErrorType defines the type of an error
 type ErrorType string
 
@@ -20,6 +27,10 @@ const (
 	ErrorTimeout ErrorType = "timeout"
 	// ErrorCancelled error
 	ErrorCancelled ErrorType = "cancelled"
+	// ErrorUnavailable error
+	ErrorUnavailable ErrorType = "unavailable"
+	// ErrorRateLimited error
+	ErrorRateLimited ErrorType = "rate_limited"
 )
 
 // Error Severity
@@ -58,16 +69,79 @@ type Error struct {
 	errType          ErrorType
 	severity         ErrorSeverity
 	message          string
+	publicMessage    string
 	detail           string
 	cause            error
 	validationErrors FieldValidationErrors
+	retryAfter       time.Duration
+	metadata         map[string]any
+	stackTrace       StackTrace
 }
 
-// Error message
+// Error message, it may contain internal details that should only be logged
 func (e Error) Error() string {
 	return e.message
 }
 
+// Get the message safe to expose to clients, empty when it was not set
+func (e Error) PublicMessage() string {
+	return e.publicMessage
+}
+
+// Set the message safe to expose to clients, Error() keeps returning the internal message for logs
+func (e *Error) SetPublicMessage(msg string) *Error {
+	e.publicMessage = msg
+	return e
+}
+
+// Format prints the message with %s and %v, %+v adds the code, the detail, the stack trace and the cause
+func (e Error) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		fmt.Fprintf(s, "%s: %s", e.code, e.message)
+
+		if e.detail != "" {
+			fmt.Fprintf(s, "\n%s", e.detail)
+		}
+
+		if len(e.stackTrace) > 0 {
+			fmt.Fprintf(s, "\n%s", e.stackTrace)
+		}
+
+		if e.cause != nil {
+			fmt.Fprintf(s, "\ncaused by: %+v", e.cause)
+		}
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", e.message)
+	default:
+		_, _ = io.WriteString(s, e.message)
+	}
+}
+
+// LogValue logs the error as a group with its code, type, severity, cause and stack trace
+func (e Error) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("message", e.message),
+		slog.String("code", e.code),
+		slog.String("type", string(e.errType)),
+		slog.String("severity", string(e.severity)),
+	}
+
+	if e.detail != "" {
+		attrs = append(attrs, slog.String("detail", e.detail))
+	}
+
+	if e.cause != nil {
+		attrs = append(attrs, slog.String("cause", e.cause.Error()))
+	}
+
+	if len(e.stackTrace) > 0 {
+		attrs = append(attrs, slog.String("stack_trace", e.stackTrace.String()))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
 // Error code
 func (e Error) Code() string {
 	return e.code
@@ -83,17 +157,32 @@ func (e Error) Cause() error {
 	return e.cause
 }
 
+// Unwrap returns the original error cause so errors.Is and errors.As can inspect it
+func (e Error) Unwrap() error {
+	return e.cause
+}
+
 // Get the error severity
 func (e Error) Severity() ErrorSeverity {
 	return e.severity
 }
 
-// Set the original error severity
+// Set the original error severity, the stack trace is captured when it is enabled for the new severity
 func (e *Error) SetSeverity(severity ErrorSeverity) *Error {
 	e.severity = severity
+
+	if e.stackTrace == nil {
+		e.stackTrace = captureStack(severity, 0)
+	}
+
 	return e
 }
 
+// Get the stack trace captured when the error was created, nil when stack traces are disabled
+func (e Error) StackTrace() StackTrace {
+	return e.stackTrace
+}
+
 // Get the error detail
 func (e Error) Detail() string {
 	return e.detail
@@ -105,6 +194,32 @@ func (e *Error) SetDetail(detail string) *Error {
 	return e
 }
 
+// Get the delay the caller should wait before trying again
+func (e Error) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// Set the delay the caller should wait before trying again
+func (e *Error) SetRetryAfter(retryAfter time.Duration) *Error {
+	e.retryAfter = retryAfter
+	return e
+}
+
+// Get the structured metadata of the error like the resource id or the exceeded limit
+func (e Error) Metadata() map[string]any {
+	return e.metadata
+}
+
+// Set a metadata value
+func (e *Error) SetMetadata(key string, value any) *Error {
+	if e.metadata == nil {
+		e.metadata = make(map[string]any)
+	}
+
+	e.metadata[key] = value
+	return e
+}
+
 // wrap the original error cause
 func (e *Error) Wrap(err error) *Error {
 	e.cause = err
@@ -131,50 +246,66 @@ func (e *Error) AddValidationError(err FieldValidationError) {
 
 // NewError creates a application new error
 func NewError(code string, errType ErrorType, severity ErrorSeverity, msg string) *Error {
+	return newError(code, errType, severity, msg)
+}
+
+// newError must be called directly by the exported constructors so the stack trace starts at their caller
+func newError(code string, errType ErrorType, severity ErrorSeverity, msg string) *Error {
 	return &Error{
-		code:     code,
-		errType:  errType,
-		severity: severity,
-		message:  msg,
+		code:       code,
+		errType:    errType,
+		severity:   severity,
+		message:    msg,
+		stackTrace: captureStack(severity, 1),
 	}
 }
 
 // NewErrorInternal creates an error of type internal
 func NewErrorInternal(code string, msg string) *Error {
-	return NewError(code, ErrorInternal, ErrorSeverityHigh, msg)
+	return newError(code, ErrorInternal, ErrorSeverityHigh, msg)
 }
 
 // NewErrorValidation creates an error of type validation
 func NewErrorValidation(code string, msg string) *Error {
-	return NewError(code, ErrorValidation, ErrorSeverityLow, msg)
+	return newError(code, ErrorValidation, ErrorSeverityLow, msg)
 }
 
 // NewErrorNotFound creates an error of type not found
 func NewErrorNotFound(code string, msg string) *Error {
-	return NewError(code, ErrorNotFound, ErrorSeverityLow, msg)
+	return newError(code, ErrorNotFound, ErrorSeverityLow, msg)
 }
 
 // NewErrorPermission creates an error of type permission
 func NewErrorPermission(code string, msg string) *Error {
-	return NewError(code, ErrorPermission, ErrorSeverityLow, msg)
+	return newError(code, ErrorPermission, ErrorSeverityLow, msg)
 }
 
 // NewErrorUnauthorised creates an error of type unauthorised
 func NewErrorUnauthorised(code string, msg string) *Error {
-	return NewError(code, ErrorUnauthorised, ErrorSeverityLow, msg)
+	return newError(code, ErrorUnauthorised, ErrorSeverityLow, msg)
 }
 
 // NewErrorConflict creates an error of type conflict
 func NewErrorConflict(code string, msg string) *Error {
-	return NewError(code, ErrorConflict, ErrorSeverityLow, msg)
+	return newError(code, ErrorConflict, ErrorSeverityLow, msg)
 }
 
 // NewErrorTimeout creates an error of type timeout
 func NewErrorTimeout(code string, msg string) *Error {
-	return NewError(code, ErrorTimeout, ErrorSeverityLow, msg)
+	return newError(code, ErrorTimeout, ErrorSeverityLow, msg)
 }
 
 // NewErrorCancelled creates an error of type cancelled
 func NewErrorCancelled(code string, msg string) *Error {
-	return NewError(code, ErrorCancelled, ErrorSeverityLow, msg)
+	return newError(code, ErrorCancelled, ErrorSeverityLow, msg)
+}
+
+// NewErrorUnavailable creates an error of type unavailable
+func NewErrorUnavailable(code string, msg string) *Error {
+	return newError(code, ErrorUnavailable, ErrorSeverityMedium, msg)
+}
+
+// NewErrorRateLimited creates an error of type rate limited
+func NewErrorRateLimited(code string, msg string) *Error {
+	return newError(code, ErrorRateLimited, ErrorSeverityLow, msg)
 }
@@ -3,6 +3,7 @@ package app
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -23,6 +24,7 @@ func TestAppCriticalErrorWrap(t *testing.T) {
 	err := NewError("error_code", ErrorInternal, ErrorSeverityHigh, "Error while running the test").Wrap(cause).SetSeverity(ErrorSeverityCritical)
 
 	require.ErrorIs(t, cause, err.Cause())
+	require.ErrorIs(t, err, cause)
 	require.Equal(t, ErrorSeverityCritical, err.Severity())
 }
 
@@ -113,3 +115,45 @@ func TestNewCancelledError(t *testing.T) {
 	require.Equal(t, "Error Message", err.Error())
 	require.NoError(t, err.Cause())
 }
+
+func TestNewUnavailableError(t *testing.T) {
+	err := NewErrorUnavailable("error_code", "Error Message")
+
+	require.Equal(t, "error_code", err.Code())
+	require.Equal(t, ErrorUnavailable, err.Type())
+	require.Equal(t, ErrorSeverityMedium, err.Severity())
+	require.Equal(t, "Error Message", err.Error())
+	require.NoError(t, err.Cause())
+}
+
+func TestNewRateLimitedError(t *testing.T) {
+	err := NewErrorRateLimited("error_code", "Error Message").SetRetryAfter(time.Second)
+
+	require.Equal(t, "error_code", err.Code())
+	require.Equal(t, ErrorRateLimited, err.Type())
+	require.Equal(t, ErrorSeverityLow, err.Severity())
+	require.Equal(t, "Error Message", err.Error())
+	require.Equal(t, time.Second, err.RetryAfter())
+	require.NoError(t, err.Cause())
+}
+
+func TestErrorMetadata(t *testing.T) {
+	err := NewErrorNotFound("error_code", "Error Message")
+
+	require.Nil(t, err.Metadata())
+
+	err.SetMetadata("payment_id", "123").SetMetadata("limit", 10)
+
+	require.Equal(t, map[string]any{"payment_id": "123", "limit": 10}, err.Metadata())
+}
+
+func TestErrorPublicMessage(t *testing.T) {
+	err := NewErrorInternal("database_error", "pq: relation \"payments\" does not exist")
+
+	require.Empty(t, err.PublicMessage())
+
+	err.SetPublicMessage("The payment could not be saved")
+
+	require.Equal(t, "The payment could not be saved", err.PublicMessage())
+	require.Equal(t, "pq: relation \"payments\" does not exist", err.Error())
+}
@@ -0,0 +1,57 @@
+package app
+
+// GenericMessage is the message sent to clients instead of the errors that are not exposed
+const GenericMessage = "An error occurred, please contact support."
+
+// ExposurePolicy decides if the message and detail of the error can be exposed to clients
+type ExposurePolicy func(appError *Error) bool
+
+// DefaultExposurePolicy hides internal errors and errors with high or critical severity
+// since they may carry messages of the underlying dependencies like database drivers
+func DefaultExposurePolicy(appError *Error) bool {
+	if appError.Type() == ErrorInternal {
+		return false
+	}
+
+	switch appError.Severity() {
+	case ErrorSeverityHigh, ErrorSeverityCritical:
+		return false
+	default:
+		return true
+	}
+}
+
+// Expose returns the message and detail of the error that can be sent to clients, DefaultExposurePolicy is used when nil.
+// Hidden errors have the public message or the generic message and no detail.
+func Expose(appError *Error, policy ExposurePolicy) (string, string) {
+	if policy == nil {
+		policy = DefaultExposurePolicy
+	}
+
+	if !policy(appError) {
+		if appError.PublicMessage() != "" {
+			return appError.PublicMessage(), ""
+		}
+
+		return GenericMessage, ""
+	}
+
+	if appError.PublicMessage() != "" {
+		return appError.PublicMessage(), appError.Detail()
+	}
+
+	return appError.Error(), appError.Detail()
+}
+
+// DefaultSeverity returns the severity given by the constructors to the errors of the type,
+// it is used when the error is rebuilt from a transport that does not carry the severity
+func DefaultSeverity(errType ErrorType) ErrorSeverity {
+	switch errType {
+	case ErrorInternal:
+		return ErrorSeverityHigh
+	case ErrorUnavailable:
+		return ErrorSeverityMedium
+	default:
+		return ErrorSeverityLow
+	}
+}
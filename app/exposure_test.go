@@ -0,0 +1,114 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultExposurePolicy(t *testing.T) {
+	tt := []struct {
+		name            string
+		err             *Error
+		expectedExposed bool
+	}{
+		{
+			name:            "validation",
+			err:             NewErrorValidation("invalid", "invalid"),
+			expectedExposed: true,
+		},
+		{
+			name:            "unavailable",
+			err:             NewErrorUnavailable("unavailable", "unavailable"),
+			expectedExposed: true,
+		},
+		{
+			name:            "internal",
+			err:             NewErrorInternal("internal", "internal").SetSeverity(ErrorSeverityLow),
+			expectedExposed: false,
+		},
+		{
+			name:            "high severity",
+			err:             NewErrorConflict("conflict", "conflict").SetSeverity(ErrorSeverityHigh),
+			expectedExposed: false,
+		},
+		{
+			name:            "critical severity",
+			err:             NewErrorTimeout("timeout", "timeout").SetSeverity(ErrorSeverityCritical),
+			expectedExposed: false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedExposed, DefaultExposurePolicy(tc.err))
+		})
+	}
+}
+
+func TestExpose(t *testing.T) {
+	tt := []struct {
+		name            string
+		err             *Error
+		policy          ExposurePolicy
+		expectedMessage string
+		expectedDetail  string
+	}{
+		{
+			name:            "exposed",
+			err:             NewErrorNotFound("payment_not_found", "Payment not found").SetDetail("Payment 1"),
+			expectedMessage: "Payment not found",
+			expectedDetail:  "Payment 1",
+		},
+		{
+			name:            "exposed public message",
+			err:             NewErrorNotFound("payment_not_found", "payment 1 not found").SetPublicMessage("Payment not found"),
+			expectedMessage: "Payment not found",
+		},
+		{
+			name:            "hidden",
+			err:             NewErrorInternal("database_error", "pq: connection refused").SetDetail("SELECT 1"),
+			expectedMessage: GenericMessage,
+		},
+		{
+			name:            "hidden public message",
+			err:             NewErrorInternal("database_error", "pq: connection refused").SetPublicMessage("Payment not saved"),
+			expectedMessage: "Payment not saved",
+		},
+		{
+			name:            "custom policy",
+			err:             NewErrorInternal("database_error", "pq: connection refused"),
+			policy:          func(*Error) bool { return true },
+			expectedMessage: "pq: connection refused",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			message, detail := Expose(tc.err, tc.policy)
+
+			require.Equal(t, tc.expectedMessage, message)
+			require.Equal(t, tc.expectedDetail, detail)
+		})
+	}
+}
+
+func TestDefaultSeverity(t *testing.T) {
+	constructors := []func(code string, msg string) *Error{
+		NewErrorInternal,
+		NewErrorValidation,
+		NewErrorNotFound,
+		NewErrorPermission,
+		NewErrorUnauthorised,
+		NewErrorConflict,
+		NewErrorTimeout,
+		NewErrorCancelled,
+		NewErrorUnavailable,
+		NewErrorRateLimited,
+	}
+
+	for _, newError := range constructors {
+		err := newError("code", "msg")
+		require.Equal(t, err.Severity(), DefaultSeverity(err.Type()), err.Type())
+	}
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// maxStackDepth limits the number of frames captured
+const maxStackDepth = 32
+
+// StackTrace holds the program counters of the call stack where the error was created
+type StackTrace []uintptr
+
+// Frames resolves the program counters into frames
+func (st StackTrace) Frames() []runtime.Frame {
+	if len(st) == 0 {
+		return nil
+	}
+
+	var frames []runtime.Frame
+
+	callersFrames := runtime.CallersFrames(st)
+
+	for {
+		frame, more := callersFrames.Next()
+		frames = append(frames, frame)
+
+		if !more {
+			return frames
+		}
+	}
+}
+
+// String formats each frame as the function followed by its file and line
+func (st StackTrace) String() string {
+	var b strings.Builder
+
+	for i, frame := range st.Frames() {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+
+		fmt.Fprintf(&b, "%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+	}
+
+	return b.String()
+}
+
+// stackTraceConfig decides which errors capture the stack trace, it is replaced instead of mutated
+type stackTraceConfig struct {
+	all        bool
+	severities map[ErrorSeverity]bool
+}
+
+// stackTraces holds the current config so errors can be created while it is changed
+var stackTraces atomic.Pointer[stackTraceConfig]
+
+// setStackTraces replaces the config and returns a function restoring the previous one
+func setStackTraces(config *stackTraceConfig) func() {
+	previous := stackTraces.Swap(config)
+
+	return func() {
+		stackTraces.Store(previous)
+	}
+}
+
+// EnableStackTraces captures the stack trace of every error created.
+// It returns a function restoring the previous setting, e.g. t.Cleanup(app.EnableStackTraces()) in tests.
+func EnableStackTraces() func() {
+	return setStackTraces(&stackTraceConfig{all: true})
+}
+
+// EnableStackTracesFor only captures the stack trace of the errors with the given severities to limit the cost.
+// It returns a function restoring the previous setting.
+func EnableStackTracesFor(severities ...ErrorSeverity) func() {
+	config := &stackTraceConfig{severities: make(map[ErrorSeverity]bool, len(severities))}
+
+	for _, severity := range severities {
+		config.severities[severity] = true
+	}
+
+	return setStackTraces(config)
+}
+
+// DisableStackTraces stops capturing stack traces, which is the default.
+// It returns a function restoring the previous setting.
+func DisableStackTraces() func() {
+	return setStackTraces(nil)
+}
+
+// captureStack returns the stack trace when enabled for the severity.
+// skip is the number of frames between the caller of captureStack and the code creating the error.
+func captureStack(severity ErrorSeverity, skip int) StackTrace {
+	config := stackTraces.Load()
+
+	if config == nil || !(config.all || config.severities[severity]) {
+		return nil
+	}
+
+	pcs := make([]uintptr, maxStackDepth)
+	// skip runtime.Callers, captureStack and its caller
+	n := runtime.Callers(3+skip, pcs)
+
+	return pcs[:n]
+}
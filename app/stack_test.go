@@ -0,0 +1,112 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStackTraceDisabledByDefault(t *testing.T) {
+	err := NewErrorInternal("error_code", "Error Message")
+
+	require.Nil(t, err.StackTrace())
+	require.Empty(t, err.StackTrace().String())
+}
+
+func TestEnableStackTraces(t *testing.T) {
+	t.Cleanup(EnableStackTraces())
+
+	tt := []struct {
+		name string
+		err  *Error
+	}{
+		{name: "NewError", err: NewError("error_code", ErrorInternal, ErrorSeverityHigh, "Error Message")},
+		{name: "NewErrorInternal", err: NewErrorInternal("error_code", "Error Message")},
+		{name: "NewErrorValidation", err: NewErrorValidation("error_code", "Error Message")},
+		{name: "NewErrorRateLimited", err: NewErrorRateLimited("error_code", "Error Message")},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			frames := tc.err.StackTrace().Frames()
+
+			require.NotEmpty(t, frames)
+			require.Equal(t, "github.com/Talento90/goliath/app.TestEnableStackTraces", frames[0].Function)
+			require.True(t, strings.HasSuffix(frames[0].File, "stack_test.go"))
+		})
+	}
+}
+
+func TestEnableStackTracesFor(t *testing.T) {
+	t.Cleanup(EnableStackTracesFor(ErrorSeverityCritical))
+
+	require.Nil(t, NewErrorInternal("error_code", "Error Message").StackTrace())
+	require.Nil(t, NewErrorValidation("error_code", "Error Message").StackTrace())
+
+	err := NewErrorInternal("error_code", "Error Message").SetSeverity(ErrorSeverityCritical)
+	frames := err.StackTrace().Frames()
+
+	require.NotEmpty(t, frames)
+	require.Equal(t, "github.com/Talento90/goliath/app.TestEnableStackTracesFor", frames[0].Function)
+
+	DisableStackTraces()
+
+	require.Nil(t, NewError("error_code", ErrorInternal, ErrorSeverityCritical, "Error Message").StackTrace())
+}
+
+func TestStackTracesRestorePreviousSetting(t *testing.T) {
+	t.Cleanup(EnableStackTracesFor(ErrorSeverityCritical))
+
+	restore := EnableStackTraces()
+	require.NotNil(t, NewErrorValidation("error_code", "Error Message").StackTrace())
+
+	restore()
+	require.Nil(t, NewErrorValidation("error_code", "Error Message").StackTrace())
+	require.NotNil(t, NewErrorValidation("error_code", "Error Message").SetSeverity(ErrorSeverityCritical).StackTrace())
+}
+
+func TestErrorFormat(t *testing.T) {
+	err := NewErrorInternal("error_code", "Error Message").SetDetail("More context").Wrap(errors.New("root cause"))
+
+	require.Equal(t, "Error Message", fmt.Sprintf("%s", err))
+	require.Equal(t, "Error Message", fmt.Sprintf("%v", err))
+	require.Equal(t, `"Error Message"`, fmt.Sprintf("%q", err))
+	require.Equal(t, "wrapped: Error Message", fmt.Errorf("wrapped: %w", err).Error())
+	require.Equal(t, "error_code: Error Message\nMore context\ncaused by: root cause", fmt.Sprintf("%+v", err))
+
+	t.Cleanup(EnableStackTraces())
+
+	err = NewErrorInternal("error_code", "Error Message")
+	formatted := fmt.Sprintf("%+v", err)
+
+	require.True(t, strings.HasPrefix(formatted, "error_code: Error Message\ngithub.com/Talento90/goliath/app.TestErrorFormat\n\t"))
+	require.Contains(t, formatted, "stack_test.go:")
+	require.Equal(t, "Error Message", fmt.Sprintf("%v", err))
+}
+
+func TestErrorLogValue(t *testing.T) {
+	t.Cleanup(EnableStackTraces())
+
+	var buf bytes.Buffer
+
+	err := NewErrorInternal("error_code", "Error Message").Wrap(errors.New("root cause"))
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("request failed", "error", err)
+
+	var entry struct {
+		Error map[string]string `json:"error"`
+	}
+
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	require.Equal(t, "Error Message", entry.Error["message"])
+	require.Equal(t, "error_code", entry.Error["code"])
+	require.Equal(t, "internal", entry.Error["type"])
+	require.Equal(t, "high", entry.Error["severity"])
+	require.Equal(t, "root cause", entry.Error["cause"])
+	require.Contains(t, entry.Error["stack_trace"], "app.TestErrorLogValue")
+}
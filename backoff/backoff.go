@@ -0,0 +1,187 @@
+package backoff
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Backoff calculates the delay to wait before the next retry attempt.
+// A strategy is shared by every execution using the same config, so implementations must be safe
+// for concurrent use and must not keep state between calls since concurrent executions would mix it.
+type Backoff interface {
+	// Delay returns the delay for the given retry attempt, attempts start at 1
+	Delay(attempt int) time.Duration
+}
+
+// Func adapts an ordinary function into a Backoff
+type Func func(attempt int) time.Duration
+
+// Delay calls f(attempt)
+func (f Func) Delay(attempt int) time.Duration {
+	return f(attempt)
+}
+
+// NewRand returns a seeded random source to get reproducible jitter strategies
+func NewRand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed)) //nolint:gosec // jitter does not require a secure random source
+}
+
+type constant struct {
+	delay time.Duration
+}
+
+// Constant waits always the same delay between attempts
+func Constant(delay time.Duration) Backoff {
+	return constant{delay: delay}
+}
+
+func (c constant) Delay(int) time.Duration {
+	return c.delay
+}
+
+type linear struct {
+	minDelay time.Duration
+	maxDelay time.Duration
+}
+
+// Linear increases the delay linearly (minDelay * attempt) capped by maxDelay
+func Linear(minDelay, maxDelay time.Duration) Backoff {
+	return linear{minDelay: minDelay, maxDelay: maxDelay}
+}
+
+func (l linear) Delay(attempt int) time.Duration {
+	attempt = max(attempt, 1)
+
+	if l.minDelay > 0 && time.Duration(attempt) > l.maxDelay/l.minDelay {
+		return l.maxDelay
+	}
+
+	return clamp(l.minDelay*time.Duration(attempt), l.minDelay, l.maxDelay)
+}
+
+type exponential struct {
+	minDelay time.Duration
+	maxDelay time.Duration
+}
+
+// Exponential doubles the delay on each attempt (minDelay * 2^(attempt-1)) capped by maxDelay
+func Exponential(minDelay, maxDelay time.Duration) Backoff {
+	return exponential{minDelay: minDelay, maxDelay: maxDelay}
+}
+
+func (e exponential) Delay(attempt int) time.Duration {
+	return exponentialDelay(e.minDelay, e.maxDelay, attempt)
+}
+
+type fullJitter struct {
+	exponential
+	rnd *lockedRand
+}
+
+// FullJitter picks a random delay between minDelay and the exponential delay
+func FullJitter(minDelay, maxDelay time.Duration, rnd *rand.Rand) Backoff {
+	return fullJitter{exponential: exponential{minDelay: minDelay, maxDelay: maxDelay}, rnd: newLockedRand(rnd)}
+}
+
+func (f fullJitter) Delay(attempt int) time.Duration {
+	return f.rnd.between(f.minDelay, f.exponential.Delay(attempt))
+}
+
+type equalJitter struct {
+	exponential
+	rnd *lockedRand
+}
+
+// EqualJitter keeps half of the exponential delay and picks the other half randomly
+func EqualJitter(minDelay, maxDelay time.Duration, rnd *rand.Rand) Backoff {
+	return equalJitter{exponential: exponential{minDelay: minDelay, maxDelay: maxDelay}, rnd: newLockedRand(rnd)}
+}
+
+func (e equalJitter) Delay(attempt int) time.Duration {
+	half := e.exponential.Delay(attempt) / 2
+
+	return clamp(half+e.rnd.between(0, half), e.minDelay, e.maxDelay)
+}
+
+type decorrelatedJitter struct {
+	minDelay time.Duration
+	maxDelay time.Duration
+	rnd      *lockedRand
+}
+
+// DecorrelatedJitter picks a random delay between minDelay and three times the previous delay capped by maxDelay.
+// The previous delays are drawn again from minDelay on every call so concurrent executions never share them.
+func DecorrelatedJitter(minDelay, maxDelay time.Duration, rnd *rand.Rand) Backoff {
+	return decorrelatedJitter{minDelay: minDelay, maxDelay: maxDelay, rnd: newLockedRand(rnd)}
+}
+
+func (d decorrelatedJitter) Delay(attempt int) time.Duration {
+	delay := d.minDelay
+
+	for i := 0; i < max(attempt, 1); i++ {
+		upper := d.maxDelay
+
+		if delay < d.maxDelay/3 {
+			upper = delay * 3
+		}
+
+		delay = clamp(d.rnd.between(d.minDelay, upper), d.minDelay, d.maxDelay)
+	}
+
+	return delay
+}
+
+func exponentialDelay(minDelay, maxDelay time.Duration, attempt int) time.Duration {
+	attempt = max(attempt, 1)
+	delay := minDelay
+
+	for i := 1; i < attempt; i++ {
+		if delay >= maxDelay/2 {
+			return maxDelay
+		}
+
+		delay *= 2
+	}
+
+	return clamp(delay, minDelay, maxDelay)
+}
+
+// clamp keeps the delay within [minDelay, maxDelay]
+func clamp(delay, minDelay, maxDelay time.Duration) time.Duration {
+	if delay < minDelay {
+		return minDelay
+	}
+
+	if delay > maxDelay {
+		return maxDelay
+	}
+
+	return delay
+}
+
+// lockedRand allows sharing the same random source across go routines
+type lockedRand struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+func newLockedRand(rnd *rand.Rand) *lockedRand {
+	if rnd == nil {
+		rnd = NewRand(rand.Uint64()) //nolint:gosec // jitter does not require a secure random source
+	}
+
+	return &lockedRand{rnd: rnd}
+}
+
+// between returns a random duration in the interval [low, high]
+func (l *lockedRand) between(low, high time.Duration) time.Duration {
+	if high <= low {
+		return low
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return low + time.Duration(l.rnd.Int64N(int64(high-low)+1))
+}
@@ -0,0 +1,96 @@
+package backoff
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstant(t *testing.T) {
+	b := Constant(time.Second)
+
+	require.Equal(t, time.Second, b.Delay(1))
+	require.Equal(t, time.Second, b.Delay(10))
+}
+
+func TestLinear(t *testing.T) {
+	b := Linear(100*time.Millisecond, 250*time.Millisecond)
+
+	require.Equal(t, 100*time.Millisecond, b.Delay(0))
+	require.Equal(t, 100*time.Millisecond, b.Delay(1))
+	require.Equal(t, 200*time.Millisecond, b.Delay(2))
+	require.Equal(t, 250*time.Millisecond, b.Delay(3))
+	require.Equal(t, 250*time.Millisecond, b.Delay(1000))
+}
+
+func TestExponential(t *testing.T) {
+	b := Exponential(100*time.Millisecond, time.Second)
+
+	require.Equal(t, 100*time.Millisecond, b.Delay(1))
+	require.Equal(t, 200*time.Millisecond, b.Delay(2))
+	require.Equal(t, 400*time.Millisecond, b.Delay(3))
+	require.Equal(t, 800*time.Millisecond, b.Delay(4))
+	require.Equal(t, time.Second, b.Delay(5))
+	require.Equal(t, time.Second, b.Delay(1000))
+}
+
+func TestFunc(t *testing.T) {
+	b := Func(func(attempt int) time.Duration {
+		return time.Duration(attempt) * time.Minute
+	})
+
+	require.Equal(t, 2*time.Minute, b.Delay(2))
+}
+
+func TestJitterStrategiesWithinBounds(t *testing.T) {
+	tt := []struct {
+		name    string
+		backoff Backoff
+	}{
+		{name: "full jitter", backoff: FullJitter(10*time.Millisecond, time.Second, NewRand(1))},
+		{name: "equal jitter", backoff: EqualJitter(10*time.Millisecond, time.Second, NewRand(1))},
+		{name: "decorrelated jitter", backoff: DecorrelatedJitter(10*time.Millisecond, time.Second, NewRand(1))},
+		{name: "default random source", backoff: FullJitter(10*time.Millisecond, time.Second, nil)},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			for attempt := 1; attempt <= 50; attempt++ {
+				delay := tc.backoff.Delay(attempt)
+
+				require.GreaterOrEqual(t, delay, 10*time.Millisecond)
+				require.LessOrEqual(t, delay, time.Second)
+			}
+		})
+	}
+}
+
+func TestJitterIsReproducibleWithSeed(t *testing.T) {
+	first := DecorrelatedJitter(10*time.Millisecond, time.Second, NewRand(42))
+	second := DecorrelatedJitter(10*time.Millisecond, time.Second, NewRand(42))
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		require.Equal(t, first.Delay(attempt), second.Delay(attempt))
+	}
+}
+
+// highSource makes every random draw return the upper bound of the interval
+type highSource struct{}
+
+func (highSource) Uint64() uint64 {
+	return math.MaxUint64
+}
+
+func TestDecorrelatedJitterDoesNotShareStateAcrossCalls(t *testing.T) {
+	backoff := DecorrelatedJitter(10*time.Millisecond, time.Second, rand.New(highSource{}))
+
+	// the first attempt of another execution does not reset the delays of the running ones
+	require.Equal(t, 30*time.Millisecond, backoff.Delay(1))
+	require.Equal(t, 90*time.Millisecond, backoff.Delay(2))
+	require.Equal(t, 30*time.Millisecond, backoff.Delay(1))
+	require.Equal(t, 270*time.Millisecond, backoff.Delay(3))
+	require.Equal(t, time.Second, backoff.Delay(5))
+}
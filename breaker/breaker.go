@@ -0,0 +1,349 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Talento90/goliath/app"
+	"github.com/Talento90/goliath/clock"
+	"github.com/Talento90/goliath/internal/window"
+)
+
+// ErrorCodeOpen is the error code returned when the circuit is open
+const ErrorCodeOpen = "circuit_open"
+
+// State of the circuit breaker
+type State int
+
+const (
+	// StateClosed lets every request pass through
+	StateClosed State = iota
+	// StateOpen rejects every request until the cool down elapses
+	StateOpen
+	// StateHalfOpen lets a limited number of trial requests pass through
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+// Config circuit breaker
+type Config struct {
+	// FailureRatio opens the circuit when the ratio of failures within the window reaches it, zero disables it
+	FailureRatio float64
+	// MinRequests within the window before the failure ratio is evaluated
+	MinRequests int
+	// ConsecutiveFailures opens the circuit when reached, zero disables it
+	ConsecutiveFailures int
+	// Window is the duration of the rolling statistics
+	Window time.Duration
+	// Buckets is the number of buckets the window is split into
+	Buckets int
+	// CoolDown is the time the circuit stays open before moving to half open
+	CoolDown time.Duration
+	// HalfOpenRequests is the number of trial requests that must succeed to close the circuit
+	HalfOpenRequests int
+	// IsFailure decides if the error counts as a failure or is ignored, DefaultIsFailure is used when nil
+	IsFailure func(err error) bool
+	// OnStateChange is called every time the circuit changes its state
+	OnStateChange func(from State, to State)
+	// Clock measures the window and the cool down, a UTC clock is used when nil
+	Clock clock.Clock
+}
+
+// NewConfig returns a config that opens the circuit after 5 consecutive failures
+// or when half of the requests failed within 10 seconds
+func NewConfig() Config {
+	return Config{
+		FailureRatio:        0.5,
+		MinRequests:         10,
+		ConsecutiveFailures: 5,
+		Window:              10 * time.Second,
+		Buckets:             10,
+		CoolDown:            30 * time.Second,
+		HalfOpenRequests:    1,
+		Clock:               clock.NewUtcClock(),
+	}
+}
+
+// DefaultIsFailure counts every error as a failure except app errors caused by the caller
+// like validation, not found, permission, unauthorised, conflict and cancelled,
+// and context.Canceled since the caller gave up before the dependency answered.
+func DefaultIsFailure(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var appErr *app.Error
+
+	if !errors.As(err, &appErr) {
+		return true
+	}
+
+	switch appErr.Type() {
+	case app.ErrorValidation, app.ErrorNotFound, app.ErrorPermission, app.ErrorUnauthorised, app.ErrorConflict,
+		app.ErrorCancelled:
+		return false
+	default:
+		return true
+	}
+}
+
+// IsOpen reports whether the error was returned because the circuit is open
+func IsOpen(err error) bool {
+	var appErr *app.Error
+	return errors.As(err, &appErr) && appErr.Code() == ErrorCodeOpen
+}
+
+// Breaker stops calling a failing dependency until it recovers
+type Breaker struct {
+	mu          sync.Mutex
+	config      Config
+	state       State
+	generation  uint64
+	openedAt    time.Time
+	window      *window.Window[results]
+	consecutive int
+	halfOpen    int
+	successes   int
+	transitions []transition
+}
+
+// results counts the requests within a bucket of the window
+type results struct {
+	successes int
+	failures  int
+}
+
+// outcome of a request executed through the breaker
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	// outcomeIgnored is an error that is not a failure, it leaves the statistics untouched
+	outcomeIgnored
+)
+
+// transition is a state change waiting to be notified
+type transition struct {
+	from State
+	to   State
+}
+
+// New returns a circuit breaker in the closed state
+func New(config Config) *Breaker {
+	if config.Clock == nil {
+		config.Clock = clock.NewUtcClock()
+	}
+
+	if config.IsFailure == nil {
+		config.IsFailure = DefaultIsFailure
+	}
+
+	if config.HalfOpenRequests <= 0 {
+		config.HalfOpenRequests = 1
+	}
+
+	return &Breaker{
+		config: config,
+		state:  StateClosed,
+		window: window.New[results](config.Window, config.Buckets),
+	}
+}
+
+// State returns the current state of the circuit
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.unlock()
+
+	b.refresh(b.config.Clock.Now())
+
+	return b.state
+}
+
+// Execute runs the task when the circuit allows it and records its result.
+// When the circuit is open it returns an app.Error of type app.ErrorUnavailable with the code ErrorCodeOpen.
+// Errors that are not failures according to IsFailure are ignored, they neither count as successes nor failures.
+// A panicking task is recorded as a failure before the panic is propagated.
+func Execute[T any](ctx context.Context, b *Breaker, task func(ctx context.Context) (T, error)) (T, error) {
+	var defaultResult T
+
+	generation, err := b.before()
+
+	if err != nil {
+		return defaultResult, err
+	}
+
+	// a panicking task is recorded as a failure so the half open trial request is released
+	result := outcomeFailure
+
+	defer func() {
+		b.after(generation, result)
+	}()
+
+	value, err := task(ctx)
+
+	switch {
+	case err == nil:
+		result = outcomeSuccess
+	case !b.config.IsFailure(err):
+		result = outcomeIgnored
+	}
+
+	return value, err
+}
+
+// before checks if the request is allowed
+func (b *Breaker) before() (uint64, error) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	b.refresh(b.config.Clock.Now())
+
+	switch b.state {
+	case StateOpen:
+		return b.generation, app.NewErrorUnavailable(ErrorCodeOpen, "circuit breaker is open")
+	case StateHalfOpen:
+		if b.halfOpen >= b.config.HalfOpenRequests {
+			return b.generation, app.NewErrorUnavailable(ErrorCodeOpen, "circuit breaker is half open")
+		}
+
+		b.halfOpen++
+	}
+
+	return b.generation, nil
+}
+
+// after records the result of the request
+func (b *Breaker) after(generation uint64, result outcome) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	now := b.config.Clock.Now()
+	b.refresh(now)
+
+	// ignore results of requests started before the last state change
+	if generation != b.generation {
+		return
+	}
+
+	switch result {
+	case outcomeSuccess:
+		b.onSuccess(now)
+	case outcomeFailure:
+		b.onFailure(now)
+	case outcomeIgnored:
+		b.onIgnored()
+	}
+}
+
+func (b *Breaker) onSuccess(now time.Time) {
+	switch b.state {
+	case StateClosed:
+		b.consecutive = 0
+		b.window.Current(now).successes++
+	case StateHalfOpen:
+		b.successes++
+
+		if b.successes >= b.config.HalfOpenRequests {
+			b.setState(StateClosed, now)
+		}
+	}
+}
+
+// onIgnored releases the half open trial request without changing the state
+func (b *Breaker) onIgnored() {
+	if b.state == StateHalfOpen && b.halfOpen > 0 {
+		b.halfOpen--
+	}
+}
+
+func (b *Breaker) onFailure(now time.Time) {
+	switch b.state {
+	case StateClosed:
+		b.consecutive++
+		b.window.Current(now).failures++
+
+		if b.shouldOpen(now) {
+			b.setState(StateOpen, now)
+		}
+	case StateHalfOpen:
+		b.setState(StateOpen, now)
+	}
+}
+
+func (b *Breaker) shouldOpen(now time.Time) bool {
+	if b.config.ConsecutiveFailures > 0 && b.consecutive >= b.config.ConsecutiveFailures {
+		return true
+	}
+
+	if b.config.FailureRatio <= 0 {
+		return false
+	}
+
+	var successes, failures int
+
+	b.window.Each(now, func(r results) {
+		successes += r.successes
+		failures += r.failures
+	})
+
+	total := successes + failures
+
+	return total > 0 && total >= b.config.MinRequests && float64(failures)/float64(total) >= b.config.FailureRatio
+}
+
+// refresh moves an open circuit to half open when the cool down elapsed
+func (b *Breaker) refresh(now time.Time) {
+	if b.state == StateOpen && !now.Before(b.openedAt.Add(b.config.CoolDown)) {
+		b.setState(StateHalfOpen, now)
+	}
+}
+
+func (b *Breaker) setState(state State, now time.Time) {
+	if b.state == state {
+		return
+	}
+
+	from := b.state
+	b.state = state
+	b.generation++
+	b.consecutive = 0
+	b.halfOpen = 0
+	b.successes = 0
+
+	switch state {
+	case StateOpen:
+		b.openedAt = now
+	case StateClosed:
+		b.window.Reset()
+	}
+
+	if b.config.OnStateChange != nil {
+		b.transitions = append(b.transitions, transition{from: from, to: state})
+	}
+}
+
+// unlock releases the lock and notifies the state changes so callbacks can use the breaker
+func (b *Breaker) unlock() {
+	transitions := b.transitions
+	b.transitions = nil
+	b.mu.Unlock()
+
+	for _, t := range transitions {
+		b.config.OnStateChange(t.from, t.to)
+	}
+}
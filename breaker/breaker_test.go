@@ -0,0 +1,260 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Talento90/goliath/app"
+	"github.com/Talento90/goliath/clock"
+)
+
+func newTestBreaker(config Config) (*Breaker, *clock.Fake) {
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	config.Clock = clk
+
+	return New(config), clk
+}
+
+func succeed(context.Context) (string, error) {
+	return "ok", nil
+}
+
+func fail(context.Context) (string, error) {
+	return "", errors.New("connection refused")
+}
+
+func TestBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	b, _ := newTestBreaker(Config{ConsecutiveFailures: 3, CoolDown: time.Minute})
+
+	for i := 0; i < 3; i++ {
+		_, err := Execute(context.Background(), b, fail)
+		require.Error(t, err)
+	}
+
+	require.Equal(t, StateOpen, b.State())
+
+	called := false
+	_, err := Execute(context.Background(), b, func(context.Context) (string, error) {
+		called = true
+		return "ok", nil
+	})
+
+	var appErr *app.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, ErrorCodeOpen, appErr.Code())
+	require.Equal(t, app.ErrorUnavailable, appErr.Type())
+	require.True(t, IsOpen(err))
+	require.False(t, called)
+}
+
+func TestBreakerSuccessResetsConsecutiveFailures(t *testing.T) {
+	b, _ := newTestBreaker(Config{ConsecutiveFailures: 2, CoolDown: time.Minute})
+
+	_, _ = Execute(context.Background(), b, fail)
+	_, _ = Execute(context.Background(), b, succeed)
+	_, _ = Execute(context.Background(), b, fail)
+
+	require.Equal(t, StateClosed, b.State())
+}
+
+func TestBreakerOpensOnFailureRatio(t *testing.T) {
+	b, clk := newTestBreaker(Config{FailureRatio: 0.5, MinRequests: 4, Window: 10 * time.Second, Buckets: 10, CoolDown: time.Minute})
+
+	_, _ = Execute(context.Background(), b, succeed)
+	_, _ = Execute(context.Background(), b, fail)
+	_, _ = Execute(context.Background(), b, succeed)
+
+	require.Equal(t, StateClosed, b.State())
+
+	clk.Advance(time.Second)
+	_, _ = Execute(context.Background(), b, fail)
+
+	require.Equal(t, StateOpen, b.State())
+}
+
+func TestBreakerFailureRatioRollingWindow(t *testing.T) {
+	b, clk := newTestBreaker(Config{FailureRatio: 0.5, MinRequests: 2, Window: 10 * time.Second, Buckets: 10, CoolDown: time.Minute})
+
+	_, _ = Execute(context.Background(), b, fail)
+
+	clk.Advance(11 * time.Second)
+	_, _ = Execute(context.Background(), b, succeed)
+	_, _ = Execute(context.Background(), b, succeed)
+
+	require.Equal(t, StateClosed, b.State())
+}
+
+func TestBreakerHalfOpenCloses(t *testing.T) {
+	var transitions []string
+	b, clk := newTestBreaker(Config{
+		ConsecutiveFailures: 1,
+		CoolDown:            time.Minute,
+		HalfOpenRequests:    2,
+		OnStateChange: func(from State, to State) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+
+	_, _ = Execute(context.Background(), b, fail)
+	require.Equal(t, StateOpen, b.State())
+
+	clk.Advance(time.Minute)
+	require.Equal(t, StateHalfOpen, b.State())
+
+	_, err := Execute(context.Background(), b, succeed)
+	require.NoError(t, err)
+	require.Equal(t, StateHalfOpen, b.State())
+
+	_, err = Execute(context.Background(), b, succeed)
+	require.NoError(t, err)
+	require.Equal(t, StateClosed, b.State())
+
+	require.Equal(t, []string{"closed->open", "open->half_open", "half_open->closed"}, transitions)
+}
+
+func TestBreakerHalfOpenReopensOnFailure(t *testing.T) {
+	b, clk := newTestBreaker(Config{ConsecutiveFailures: 1, CoolDown: time.Minute})
+
+	_, _ = Execute(context.Background(), b, fail)
+
+	clk.Advance(time.Minute)
+
+	_, err := Execute(context.Background(), b, fail)
+	require.Error(t, err)
+	require.False(t, IsOpen(err))
+	require.Equal(t, StateOpen, b.State())
+}
+
+func TestBreakerHalfOpenLimitsTrialRequests(t *testing.T) {
+	b, clk := newTestBreaker(Config{ConsecutiveFailures: 1, CoolDown: time.Minute})
+
+	_, _ = Execute(context.Background(), b, fail)
+
+	clk.Advance(time.Minute)
+
+	_, err := Execute(context.Background(), b, func(ctx context.Context) (string, error) {
+		_, err := Execute(ctx, b, succeed)
+		require.True(t, IsOpen(err))
+
+		return "ok", nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, StateClosed, b.State())
+}
+
+func TestBreakerIgnoresCallerErrors(t *testing.T) {
+	b, _ := newTestBreaker(Config{ConsecutiveFailures: 1, CoolDown: time.Minute})
+
+	_, err := Execute(context.Background(), b, func(context.Context) (string, error) {
+		return "", app.NewErrorValidation("invalid_user", "Invalid user")
+	})
+
+	require.Error(t, err)
+	require.Equal(t, StateClosed, b.State())
+}
+
+func TestNewConfig(t *testing.T) {
+	b := New(NewConfig())
+
+	require.Equal(t, StateClosed, b.State())
+	require.Equal(t, "unknown", State(10).String())
+}
+
+func TestBreakerRecordsPanicAsFailure(t *testing.T) {
+	b, clk := newTestBreaker(Config{ConsecutiveFailures: 1, CoolDown: time.Minute})
+
+	_, _ = Execute(context.Background(), b, fail)
+
+	clk.Advance(time.Minute)
+	require.Equal(t, StateHalfOpen, b.State())
+
+	require.Panics(t, func() {
+		_, _ = Execute(context.Background(), b, func(context.Context) (string, error) {
+			panic("unexpected")
+		})
+	})
+
+	require.Equal(t, StateOpen, b.State())
+
+	clk.Advance(time.Minute)
+
+	_, err := Execute(context.Background(), b, succeed)
+	require.NoError(t, err)
+	require.Equal(t, StateClosed, b.State())
+}
+
+func TestBreakerIgnoresCancellation(t *testing.T) {
+	b, _ := newTestBreaker(Config{ConsecutiveFailures: 1, CoolDown: time.Minute})
+
+	_, err := Execute(context.Background(), b, func(context.Context) (string, error) {
+		return "", fmt.Errorf("query: %w", context.Canceled)
+	})
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, err = Execute(context.Background(), b, func(context.Context) (string, error) {
+		return "", app.NewErrorCancelled("cancelled", "request cancelled")
+	})
+	require.Error(t, err)
+
+	require.Equal(t, StateClosed, b.State())
+}
+
+func TestBreakerIgnoredErrorsKeepConsecutiveFailures(t *testing.T) {
+	b, _ := newTestBreaker(Config{ConsecutiveFailures: 2, CoolDown: time.Minute})
+
+	_, _ = Execute(context.Background(), b, fail)
+	_, _ = Execute(context.Background(), b, func(context.Context) (string, error) {
+		return "", context.Canceled
+	})
+	_, _ = Execute(context.Background(), b, fail)
+
+	require.Equal(t, StateOpen, b.State())
+}
+
+func TestBreakerCancelledHalfOpenTrial(t *testing.T) {
+	b, clk := newTestBreaker(Config{ConsecutiveFailures: 1, CoolDown: time.Minute})
+
+	_, _ = Execute(context.Background(), b, fail)
+
+	clk.Advance(time.Minute)
+
+	_, err := Execute(context.Background(), b, func(context.Context) (string, error) {
+		return "", context.Canceled
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, StateHalfOpen, b.State())
+
+	// the trial request was released so a new one is allowed
+	_, err = Execute(context.Background(), b, succeed)
+	require.NoError(t, err)
+	require.Equal(t, StateClosed, b.State())
+}
+
+func TestDefaultIsFailure(t *testing.T) {
+	tt := []struct {
+		name            string
+		err             error
+		expectedFailure bool
+	}{
+		{name: "generic error", err: errors.New("connection refused"), expectedFailure: true},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, expectedFailure: true},
+		{name: "internal", err: app.NewErrorInternal("internal", "internal"), expectedFailure: true},
+		{name: "timeout", err: app.NewErrorTimeout("timeout", "timeout"), expectedFailure: true},
+		{name: "context cancelled", err: context.Canceled, expectedFailure: false},
+		{name: "wrapped context cancelled", err: app.NewErrorInternal("internal", "internal").Wrap(context.Canceled), expectedFailure: false},
+		{name: "cancelled", err: app.NewErrorCancelled("cancelled", "cancelled"), expectedFailure: false},
+		{name: "validation", err: app.NewErrorValidation("validation", "validation"), expectedFailure: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedFailure, DefaultIsFailure(tc.err))
+		})
+	}
+}
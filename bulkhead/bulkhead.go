@@ -0,0 +1,181 @@
+package bulkhead
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Talento90/goliath/app"
+	"github.com/Talento90/goliath/clock"
+)
+
+const (
+	// ErrorCodeFull is the error code returned when every slot and queue position is taken
+	ErrorCodeFull = "bulkhead_full"
+	// ErrorCodeQueueTimeout is the error code returned when the queue wait timeout is exceeded
+	ErrorCodeQueueTimeout = "bulkhead_queue_timeout"
+	// ErrorCodeCancelled is the error code returned when the context is cancelled while waiting for a slot
+	ErrorCodeCancelled = "bulkhead_cancelled"
+	// ErrorCodeTimeout is the error code returned when the context deadline is exceeded while waiting for a slot
+	ErrorCodeTimeout = "bulkhead_timeout"
+)
+
+// Config bulkhead
+type Config struct {
+	// MaxConcurrent is the number of tasks executed at the same time
+	MaxConcurrent int
+	// MaxQueued is the number of tasks waiting for a free slot, zero rejects as soon as every slot is taken
+	MaxQueued int
+	// QueueTimeout is the maximum time a task waits in the queue, zero waits until the context is done
+	QueueTimeout time.Duration
+	// Clock measures the queue timeout, a UTC clock is used when nil
+	Clock clock.Clock
+}
+
+// NewConfig returns a config that executes maxConcurrent tasks at the same time without queuing
+func NewConfig(maxConcurrent int) Config {
+	return Config{
+		MaxConcurrent: maxConcurrent,
+		Clock:         clock.NewUtcClock(),
+	}
+}
+
+// Bulkhead limits the number of concurrent calls to a dependency.
+// Queued tasks are granted the free slots in arrival order.
+type Bulkhead struct {
+	mu       sync.Mutex
+	config   Config
+	size     int
+	inFlight int
+	waiters  list.List
+}
+
+// New returns a bulkhead that can be shared across go routines
+func New(config Config) *Bulkhead {
+	if config.Clock == nil {
+		config.Clock = clock.NewUtcClock()
+	}
+
+	return &Bulkhead{
+		config: config,
+		size:   max(config.MaxConcurrent, 1),
+	}
+}
+
+// InFlight returns the number of tasks being executed
+func (b *Bulkhead) InFlight() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.inFlight
+}
+
+// Queued returns the number of tasks waiting for a free slot
+func (b *Bulkhead) Queued() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.waiters.Len()
+}
+
+// Execute runs the task when there is a free slot.
+// It returns an app.Error of type app.ErrorUnavailable when the bulkhead is full or the queue timeout is exceeded
+// and an app.Error of type app.ErrorTimeout or app.ErrorCancelled wrapping the context error when the context is done.
+func Execute[T any](ctx context.Context, b *Bulkhead, task func(ctx context.Context) (T, error)) (T, error) {
+	var defaultResult T
+
+	if err := b.acquire(ctx); err != nil {
+		return defaultResult, err
+	}
+
+	defer b.release()
+
+	return task(ctx)
+}
+
+func (b *Bulkhead) acquire(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return contextError(ctx)
+	}
+
+	b.mu.Lock()
+
+	// new tasks only take a free slot when nobody is queued so they cannot jump ahead of the queue
+	if b.inFlight < b.size && b.waiters.Len() == 0 {
+		b.inFlight++
+		b.mu.Unlock()
+
+		return nil
+	}
+
+	if b.waiters.Len() >= b.config.MaxQueued {
+		b.mu.Unlock()
+		return app.NewErrorUnavailable(ErrorCodeFull, "bulkhead is full")
+	}
+
+	ready := make(chan struct{})
+	waiter := b.waiters.PushBack(ready)
+	b.mu.Unlock()
+
+	var timeout <-chan time.Time
+
+	if b.config.QueueTimeout > 0 {
+		timer := b.config.Clock.NewTimer(b.config.QueueTimeout)
+		defer timer.Stop()
+
+		timeout = timer.C()
+	}
+
+	var err error
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		err = contextError(ctx)
+	case <-timeout:
+		err = app.NewErrorUnavailable(ErrorCodeQueueTimeout, "bulkhead queue timeout exceeded")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	select {
+	case <-ready:
+		// the slot was granted while giving up so it is passed to the next task
+		b.inFlight--
+		b.grant()
+	default:
+		b.waiters.Remove(waiter)
+	}
+
+	return err
+}
+
+func (b *Bulkhead) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.inFlight--
+	b.grant()
+}
+
+// grant hands the free slots to the queued tasks in arrival order
+func (b *Bulkhead) grant() {
+	for b.inFlight < b.size && b.waiters.Len() > 0 {
+		ready, _ := b.waiters.Remove(b.waiters.Front()).(chan struct{})
+		b.inFlight++
+		close(ready)
+	}
+}
+
+// contextError converts the context error into an app.Error wrapping it
+func contextError(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return app.NewErrorTimeout(ErrorCodeTimeout, "bulkhead wait deadline exceeded").Wrap(ctx.Err())
+	}
+
+	return app.NewErrorCancelled(ErrorCodeCancelled, "bulkhead wait cancelled").Wrap(ctx.Err())
+}
@@ -0,0 +1,195 @@
+package bulkhead
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Talento90/goliath/app"
+	"github.com/Talento90/goliath/clock"
+)
+
+// block executes a task that holds its slot until release is closed
+func block(t *testing.T, b *Bulkhead, release chan struct{}) chan error {
+	t.Helper()
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := Execute(context.Background(), b, func(context.Context) (int, error) {
+			<-release
+			return 1, nil
+		})
+		done <- err
+	}()
+
+	return done
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	require.Eventually(t, condition, time.Second, time.Millisecond)
+}
+
+func TestExecute(t *testing.T) {
+	b := New(NewConfig(1))
+
+	result, err := Execute(context.Background(), b, func(context.Context) (string, error) {
+		require.Equal(t, 1, b.InFlight())
+		return "My result", nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "My result", result)
+	require.Equal(t, 0, b.InFlight())
+}
+
+func TestExecuteRejectsWhenFull(t *testing.T) {
+	b := New(NewConfig(1))
+	release := make(chan struct{})
+	done := block(t, b, release)
+
+	waitFor(t, func() bool { return b.InFlight() == 1 })
+
+	_, err := Execute(context.Background(), b, func(context.Context) (int, error) {
+		return 1, nil
+	})
+
+	var appErr *app.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, ErrorCodeFull, appErr.Code())
+	require.Equal(t, app.ErrorUnavailable, appErr.Type())
+
+	close(release)
+	require.NoError(t, <-done)
+}
+
+func TestExecuteQueuesUntilSlotIsFree(t *testing.T) {
+	b := New(Config{MaxConcurrent: 1, MaxQueued: 1})
+	release := make(chan struct{})
+	first := block(t, b, release)
+
+	waitFor(t, func() bool { return b.InFlight() == 1 })
+
+	second := block(t, b, release)
+
+	waitFor(t, func() bool { return b.Queued() == 1 })
+
+	_, err := Execute(context.Background(), b, func(context.Context) (int, error) {
+		return 1, nil
+	})
+	require.Error(t, err)
+
+	close(release)
+	require.NoError(t, <-first)
+	require.NoError(t, <-second)
+	require.Equal(t, 0, b.Queued())
+}
+
+func TestExecuteQueueTimeout(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := New(Config{MaxConcurrent: 1, MaxQueued: 1, QueueTimeout: time.Second, Clock: clk})
+	release := make(chan struct{})
+	first := block(t, b, release)
+
+	waitFor(t, func() bool { return b.InFlight() == 1 })
+
+	second := block(t, b, release)
+
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+
+	var appErr *app.Error
+	require.ErrorAs(t, <-second, &appErr)
+	require.Equal(t, ErrorCodeQueueTimeout, appErr.Code())
+
+	close(release)
+	require.NoError(t, <-first)
+}
+
+func TestExecuteQueueContextCancelled(t *testing.T) {
+	b := New(Config{MaxConcurrent: 1, MaxQueued: 1})
+	release := make(chan struct{})
+	first := block(t, b, release)
+
+	waitFor(t, func() bool { return b.InFlight() == 1 })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := Execute(ctx, b, func(context.Context) (int, error) {
+			return 1, nil
+		})
+		done <- err
+	}()
+
+	waitFor(t, func() bool { return b.Queued() == 1 })
+	cancel()
+
+	err := <-done
+
+	var appErr *app.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, ErrorCodeCancelled, appErr.Code())
+	require.Equal(t, app.ErrorCancelled, appErr.Type())
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 0, b.Queued())
+
+	close(release)
+	require.NoError(t, <-first)
+}
+
+func TestExecuteQueueContextDeadlineExceeded(t *testing.T) {
+	b := New(Config{MaxConcurrent: 1, MaxQueued: 1})
+	release := make(chan struct{})
+	first := block(t, b, release)
+
+	waitFor(t, func() bool { return b.InFlight() == 1 })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	_, err := Execute(ctx, b, func(context.Context) (int, error) {
+		return 1, nil
+	})
+
+	var appErr *app.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, ErrorCodeTimeout, appErr.Code())
+	require.Equal(t, app.ErrorTimeout, appErr.Type())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, <-first)
+}
+
+func TestExecuteGrantsSlotsInArrivalOrder(t *testing.T) {
+	b := New(Config{MaxConcurrent: 1, MaxQueued: 1})
+	releaseFirst := make(chan struct{})
+	first := block(t, b, releaseFirst)
+
+	waitFor(t, func() bool { return b.InFlight() == 1 })
+
+	releaseQueued := make(chan struct{})
+	queued := block(t, b, releaseQueued)
+
+	waitFor(t, func() bool { return b.Queued() == 1 })
+
+	close(releaseFirst)
+	require.NoError(t, <-first)
+
+	// the free slot was handed to the queued task so a new task is queued behind it
+	require.Equal(t, 1, b.InFlight())
+	require.Equal(t, 0, b.Queued())
+
+	late := block(t, b, releaseQueued)
+
+	waitFor(t, func() bool { return b.Queued() == 1 })
+
+	close(releaseQueued)
+	require.NoError(t, <-queued)
+	require.NoError(t, <-late)
+}
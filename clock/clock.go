@@ -2,9 +2,42 @@ package clock
 
 import "time"
 
-// Clock interface allows to get the current time
+// Clock interface allows to get the current time and to wait for it
 type Clock interface {
+	// Now returns the current time
 	Now() time.Time
+	// Since returns the time elapsed since t
+	Since(t time.Time) time.Duration
+	// Until returns the duration until t
+	Until(t time.Time) time.Duration
+	// After waits for the duration to elapse and then sends the current time on the returned channel
+	After(d time.Duration) <-chan time.Time
+	// NewTimer creates a new Timer that will send the current time on its channel after at least duration d
+	NewTimer(d time.Duration) Timer
+	// NewTicker returns a new Ticker that sends the current time on its channel every period d
+	NewTicker(d time.Duration) Ticker
+	// AfterFunc waits for the duration to elapse and then calls f
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer represents a single event
+type Timer interface {
+	// C returns the channel on which the time is delivered
+	C() <-chan time.Time
+	// Stop prevents the Timer from firing, it returns false if the timer already expired or was stopped
+	Stop() bool
+	// Reset changes the timer to expire after duration d, it returns true if the timer had been active
+	Reset(d time.Duration) bool
+}
+
+// Ticker delivers ticks at intervals
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered
+	C() <-chan time.Time
+	// Stop turns off the ticker
+	Stop()
+	// Reset stops the ticker and resets its period to the specified duration
+	Reset(d time.Duration)
 }
 
 type clock struct {
@@ -33,3 +66,59 @@ func New(location *time.Location) Clock {
 func (c clock) Now() time.Time {
 	return time.Now().In(c.location)
 }
+
+func (clock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+func (clock) Until(t time.Time) time.Duration {
+	return time.Until(t)
+}
+
+func (clock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (clock) NewTimer(d time.Duration) Timer {
+	return &timer{timer: time.NewTimer(d)}
+}
+
+func (clock) NewTicker(d time.Duration) Ticker {
+	return &ticker{ticker: time.NewTicker(d)}
+}
+
+func (clock) AfterFunc(d time.Duration, f func()) Timer {
+	return &timer{timer: time.AfterFunc(d, f)}
+}
+
+type timer struct {
+	timer *time.Timer
+}
+
+func (t *timer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t *timer) Stop() bool {
+	return t.timer.Stop()
+}
+
+func (t *timer) Reset(d time.Duration) bool {
+	return t.timer.Reset(d)
+}
+
+type ticker struct {
+	ticker *time.Ticker
+}
+
+func (t *ticker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+func (t *ticker) Stop() {
+	t.ticker.Stop()
+}
+
+func (t *ticker) Reset(d time.Duration) {
+	t.ticker.Reset(d)
+}
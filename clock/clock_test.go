@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewUtcClock(t *testing.T) {
@@ -32,3 +33,28 @@ func TestNewWithLocation(t *testing.T) {
 
 	assert.Contains(t, now, "CST")
 }
+
+func TestClockTimers(t *testing.T) {
+	c := NewUtcClock()
+	start := c.Now()
+
+	<-c.After(time.Millisecond)
+
+	require.GreaterOrEqual(t, c.Since(start), time.Millisecond)
+	require.Greater(t, c.Until(start.Add(time.Hour)), time.Duration(0))
+
+	timer := c.NewTimer(time.Hour)
+	require.True(t, timer.Reset(time.Millisecond))
+	<-timer.C()
+	require.False(t, timer.Stop())
+
+	ticker := c.NewTicker(time.Millisecond)
+	<-ticker.C()
+	ticker.Reset(time.Millisecond)
+	<-ticker.C()
+	ticker.Stop()
+
+	called := make(chan struct{})
+	c.AfterFunc(time.Millisecond, func() { close(called) })
+	<-called
+}
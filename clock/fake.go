@@ -0,0 +1,243 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake is a Clock controlled by the tests.
+// Time only moves when Advance or Set are called, firing the timers and tickers that expired.
+type Fake struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeTimer
+}
+
+// NewFake returns a fake clock starting at the given time
+func NewFake(start time.Time) *Fake {
+	f := &Fake{now: start}
+	f.cond = sync.NewCond(&f.mu)
+
+	return f
+}
+
+// Now returns the current fake time
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+// Since returns the fake time elapsed since t
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// Until returns the fake duration until t
+func (f *Fake) Until(t time.Time) time.Duration {
+	return t.Sub(f.Now())
+}
+
+// After returns a channel that receives the fake time once it is advanced by d
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+// NewTimer returns a timer that fires once the fake time is advanced by d
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	return f.schedule(d, 0, nil)
+}
+
+// NewTicker returns a ticker that fires every time the fake time is advanced by d
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+
+	return &fakeTicker{fakeTimer: f.schedule(d, d, nil)}
+}
+
+// AfterFunc calls f once the fake time is advanced by d.
+// The function runs on the go routine that advances the clock.
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	return f.schedule(d, 0, fn)
+}
+
+// Advance moves the fake time forward firing every expired timer and ticker
+func (f *Fake) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set moves the fake time to t firing every expired timer and ticker
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	f.now = t
+
+	var funcs []func()
+
+	for {
+		w := f.next()
+
+		if w == nil {
+			break
+		}
+
+		if fn := w.fire(); fn != nil {
+			funcs = append(funcs, fn)
+		}
+	}
+
+	f.cond.Broadcast()
+	f.mu.Unlock()
+
+	for _, fn := range funcs {
+		fn()
+	}
+}
+
+// BlockUntil blocks until there are at least n active timers and tickers waiting on the clock.
+// It allows synchronising with go routines that are about to wait on the fake time.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for len(f.waiters) < n {
+		f.cond.Wait()
+	}
+}
+
+// schedule registers a new timer
+func (f *Fake) schedule(d time.Duration, period time.Duration, fn func()) *fakeTimer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	t := &fakeTimer{
+		clock:  f,
+		ch:     make(chan time.Time, 1),
+		period: period,
+		fn:     fn,
+	}
+
+	f.add(t, d)
+
+	return t
+}
+
+// add activates the timer, must be called holding the lock
+func (f *Fake) add(t *fakeTimer, d time.Duration) {
+	t.until = f.now.Add(d)
+	f.waiters = append(f.waiters, t)
+	f.cond.Broadcast()
+
+	if !t.until.After(f.now) {
+		f.remove(t)
+
+		if fn := t.fire(); fn != nil {
+			go fn()
+		}
+	}
+}
+
+// remove deactivates the timer, must be called holding the lock
+func (f *Fake) remove(t *fakeTimer) bool {
+	for i, w := range f.waiters {
+		if w == t {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// next removes and returns the first expired timer, must be called holding the lock
+func (f *Fake) next() *fakeTimer {
+	sort.SliceStable(f.waiters, func(i, j int) bool {
+		return f.waiters[i].until.Before(f.waiters[j].until)
+	})
+
+	if len(f.waiters) == 0 || f.waiters[0].until.After(f.now) {
+		return nil
+	}
+
+	w := f.waiters[0]
+	f.waiters = f.waiters[1:]
+
+	return w
+}
+
+// fakeTimer is a timer driven by the fake clock, tickers are timers with a period
+type fakeTimer struct {
+	clock  *Fake
+	ch     chan time.Time
+	until  time.Time
+	period time.Duration
+	fn     func()
+}
+
+// fire delivers the tick and reschedules tickers, must be called holding the clock lock.
+// It returns the function to call for timers created by AfterFunc.
+func (t *fakeTimer) fire() func() {
+	if t.period > 0 {
+		t.until = t.until.Add(t.period)
+		t.clock.waiters = append(t.clock.waiters, t)
+	}
+
+	if t.fn != nil {
+		return t.fn
+	}
+
+	// drop the tick when the receiver is not keeping up like time.Ticker
+	select {
+	case t.ch <- t.clock.now:
+	default:
+	}
+
+	return nil
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	return t.clock.remove(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	active := t.clock.remove(t)
+
+	if t.period > 0 {
+		t.period = d
+	}
+
+	t.clock.add(t, d)
+
+	return active
+}
+
+// fakeTicker adapts the fake timer to the Ticker interface
+type fakeTicker struct {
+	*fakeTimer
+}
+
+func (t *fakeTicker) Stop() {
+	t.fakeTimer.Stop()
+}
+
+func (t *fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for Ticker.Reset")
+	}
+
+	t.fakeTimer.Reset(d)
+}
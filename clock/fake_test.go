@@ -0,0 +1,105 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFakeNowAdvanceAndSet(t *testing.T) {
+	c := NewFake(start)
+
+	require.Equal(t, start, c.Now())
+
+	c.Advance(time.Hour)
+	require.Equal(t, start.Add(time.Hour), c.Now())
+	require.Equal(t, time.Hour, c.Since(start))
+	require.Equal(t, time.Hour, c.Until(start.Add(2*time.Hour)))
+
+	c.Set(start)
+	require.Equal(t, start, c.Now())
+}
+
+func TestFakeTimer(t *testing.T) {
+	c := NewFake(start)
+	timer := c.NewTimer(time.Second)
+
+	c.Advance(999 * time.Millisecond)
+	require.Empty(t, timer.C())
+
+	c.Advance(time.Millisecond)
+	require.Equal(t, start.Add(time.Second), <-timer.C())
+	require.False(t, timer.Stop())
+
+	require.False(t, timer.Reset(time.Second))
+	require.True(t, timer.Stop())
+
+	c.Advance(time.Hour)
+	require.Empty(t, timer.C())
+}
+
+func TestFakeAfter(t *testing.T) {
+	c := NewFake(start)
+	ch := c.After(time.Minute)
+
+	c.Advance(time.Hour)
+
+	require.Equal(t, start.Add(time.Hour), <-ch)
+	require.Equal(t, start.Add(time.Hour), <-c.After(0))
+}
+
+func TestFakeTicker(t *testing.T) {
+	c := NewFake(start)
+	ticker := c.NewTicker(time.Second)
+
+	c.Advance(time.Second)
+	require.Equal(t, start.Add(time.Second), <-ticker.C())
+
+	c.Advance(time.Second)
+	require.Equal(t, start.Add(2*time.Second), <-ticker.C())
+
+	// ticks are dropped when the receiver is not keeping up
+	c.Advance(5 * time.Second)
+	require.Len(t, ticker.C(), 1)
+	<-ticker.C()
+
+	ticker.Reset(time.Minute)
+	c.Advance(time.Second)
+	require.Empty(t, ticker.C())
+
+	ticker.Stop()
+	c.Advance(time.Hour)
+	require.Empty(t, ticker.C())
+
+	require.Panics(t, func() { c.NewTicker(0) })
+	require.Panics(t, func() { ticker.Reset(0) })
+}
+
+func TestFakeAfterFunc(t *testing.T) {
+	c := NewFake(start)
+	calls := 0
+
+	c.AfterFunc(time.Second, func() { calls++ })
+
+	c.Advance(time.Second)
+	c.Advance(time.Second)
+
+	require.Equal(t, 1, calls)
+}
+
+func TestFakeBlockUntil(t *testing.T) {
+	c := NewFake(start)
+	done := make(chan time.Time)
+
+	go func() {
+		done <- <-c.After(time.Second)
+	}()
+
+	c.BlockUntil(1)
+	c.Advance(time.Second)
+
+	require.Equal(t, start.Add(time.Second), <-done)
+}
@@ -0,0 +1,88 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/Talento90/goliath/app"
+)
+
+// Matcher selects the errors that trigger the fallback
+type Matcher func(err error) bool
+
+// Type matches app errors of any of the types
+func Type(types ...app.ErrorType) Matcher {
+	return func(err error) bool {
+		var appErr *app.Error
+		return errors.As(err, &appErr) && slices.Contains(types, appErr.Type())
+	}
+}
+
+// Severity matches app errors of any of the severities
+func Severity(severities ...app.ErrorSeverity) Matcher {
+	return func(err error) bool {
+		var appErr *app.Error
+		return errors.As(err, &appErr) && slices.Contains(severities, appErr.Severity())
+	}
+}
+
+// Code matches app errors with any of the codes
+func Code(codes ...string) Matcher {
+	return func(err error) bool {
+		var appErr *app.Error
+		return errors.As(err, &appErr) && slices.Contains(codes, appErr.Code())
+	}
+}
+
+// Is matches errors that are any of the targets using errors.Is
+func Is(targets ...error) Matcher {
+	return func(err error) bool {
+		for _, target := range targets {
+			if errors.Is(err, target) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Result of the execution
+type Result[T any] struct {
+	// Value returned by the task or by the fallback
+	Value T
+	// Fallback reports whether the fallback was called
+	Fallback bool
+	// Err is the task error that triggered the fallback
+	Err error
+}
+
+// Execute runs the task and calls the fallback when the task error matches any of the matchers.
+// Every error triggers the fallback when no matchers are given, errors that do not match are returned as is.
+func Execute[T any](ctx context.Context, task func(ctx context.Context) (T, error), fallback func(ctx context.Context, err error) (T, error), matchers ...Matcher) (Result[T], error) {
+	value, err := task(ctx)
+
+	if err == nil || !Matches(err, matchers...) {
+		return Result[T]{Value: value}, err
+	}
+
+	value, fallbackErr := fallback(ctx, err)
+
+	return Result[T]{Value: value, Fallback: true, Err: err}, fallbackErr
+}
+
+// Matches reports whether the error matches any of the matchers, every error matches when no matchers are given
+func Matches(err error, matchers ...Matcher) bool {
+	if len(matchers) == 0 {
+		return true
+	}
+
+	for _, matcher := range matchers {
+		if matcher(err) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,109 @@
+package fallback
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Talento90/goliath/app"
+)
+
+func TestMatchers(t *testing.T) {
+	timeoutErr := fmt.Errorf("fetch: %w", app.NewErrorTimeout("db_timeout", "Database timeout"))
+	criticalErr := app.NewErrorInternal("db_down", "Database down").SetSeverity(app.ErrorSeverityCritical)
+	genericErr := fmt.Errorf("fetch: %w", sql.ErrNoRows)
+
+	tt := []struct {
+		name    string
+		matcher Matcher
+		err     error
+		matches bool
+	}{
+		{name: "type matches", matcher: Type(app.ErrorInternal, app.ErrorTimeout), err: timeoutErr, matches: true},
+		{name: "type does not match", matcher: Type(app.ErrorValidation), err: timeoutErr, matches: false},
+		{name: "type generic error", matcher: Type(app.ErrorInternal), err: genericErr, matches: false},
+		{name: "severity matches", matcher: Severity(app.ErrorSeverityCritical), err: criticalErr, matches: true},
+		{name: "severity does not match", matcher: Severity(app.ErrorSeverityHigh), err: criticalErr, matches: false},
+		{name: "code matches", matcher: Code("db_timeout"), err: timeoutErr, matches: true},
+		{name: "code does not match", matcher: Code("db_down"), err: timeoutErr, matches: false},
+		{name: "is matches", matcher: Is(context.Canceled, sql.ErrNoRows), err: genericErr, matches: true},
+		{name: "is does not match", matcher: Is(context.Canceled), err: genericErr, matches: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.matches, tc.matcher(tc.err))
+		})
+	}
+}
+
+func TestExecuteSuccess(t *testing.T) {
+	result, err := Execute(context.Background(),
+		func(context.Context) (string, error) {
+			return "My result", nil
+		},
+		func(context.Context, error) (string, error) {
+			return "cached", nil
+		},
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, Result[string]{Value: "My result"}, result)
+}
+
+func TestExecuteFallback(t *testing.T) {
+	expectedErr := app.NewErrorTimeout("db_timeout", "Database timeout")
+
+	result, err := Execute(context.Background(),
+		func(context.Context) (string, error) {
+			return "", expectedErr
+		},
+		func(_ context.Context, err error) (string, error) {
+			require.ErrorIs(t, err, expectedErr)
+			return "cached", nil
+		},
+		Type(app.ErrorTimeout, app.ErrorInternal),
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, "cached", result.Value)
+	require.True(t, result.Fallback)
+	require.ErrorIs(t, result.Err, expectedErr)
+}
+
+func TestExecuteErrorDoesNotMatch(t *testing.T) {
+	expectedErr := app.NewErrorValidation("invalid_user", "Invalid user")
+
+	result, err := Execute(context.Background(),
+		func(context.Context) (string, error) {
+			return "", expectedErr
+		},
+		func(context.Context, error) (string, error) {
+			return "cached", nil
+		},
+		Type(app.ErrorTimeout, app.ErrorInternal),
+	)
+
+	require.ErrorIs(t, err, expectedErr)
+	require.False(t, result.Fallback)
+}
+
+func TestExecuteFallbackFails(t *testing.T) {
+	expectedErr := errors.New("cache unavailable")
+
+	result, err := Execute(context.Background(),
+		func(context.Context) (string, error) {
+			return "", errors.New("connection refused")
+		},
+		func(context.Context, error) (string, error) {
+			return "", expectedErr
+		},
+	)
+
+	require.ErrorIs(t, err, expectedErr)
+	require.True(t, result.Fallback)
+}
@@ -0,0 +1,252 @@
+package grpcerror
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/Talento90/goliath/app"
+)
+
+const (
+	// TraceIDKey is the ErrorInfo metadata key holding the trace id
+	TraceIDKey = "trace_id"
+	// TypeKey is the ErrorInfo metadata key holding the app.ErrorType
+	TypeKey = "type"
+)
+
+// Option customises how the status is built
+type Option func(o *options)
+
+type options struct {
+	domain   string
+	exposure app.ExposurePolicy
+}
+
+// WithDomain sets the ErrorInfo domain, the logical grouping of the error codes like the service name
+func WithDomain(domain string) Option {
+	return func(o *options) {
+		o.domain = domain
+	}
+}
+
+// WithExposurePolicy decides which error messages are sent to clients instead of the app.DefaultExposurePolicy
+func WithExposurePolicy(policy app.ExposurePolicy) Option {
+	return func(o *options) {
+		o.exposure = policy
+	}
+}
+
+func newOptions(opts []Option) options {
+	var o options
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// New converts the error into a gRPC status with the code matching the app.ErrorType.
+// The validation errors are attached as BadRequest field violations, the error code and trace id as ErrorInfo,
+// the trace id as RequestInfo and the retry after delay as RetryInfo.
+// The message of the errors hidden by the exposure policy is replaced by the public message or app.GenericMessage.
+func New(ctx app.Context, err error, opts ...Option) *status.Status {
+	o := newOptions(opts)
+
+	var appError *app.Error
+
+	if !errors.As(err, &appError) {
+		if st, ok := status.FromError(err); ok {
+			return st
+		}
+
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return status.FromContextError(err)
+		}
+
+		return withDetails(status.New(codes.Internal, app.GenericMessage), &errdetails.RequestInfo{RequestId: ctx.TraceID()})
+	}
+
+	message, _ := app.Expose(appError, o.exposure)
+	st := status.New(Code(appError.Type()), message)
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{
+			Reason: appError.Code(),
+			Domain: o.domain,
+			Metadata: map[string]string{
+				TraceIDKey: ctx.TraceID(),
+				TypeKey:    string(appError.Type()),
+			},
+		},
+		&errdetails.RequestInfo{RequestId: ctx.TraceID()},
+	}
+
+	if violations := fieldViolations(appError.ValidationErrors()); len(violations) > 0 {
+		details = append(details, &errdetails.BadRequest{FieldViolations: violations})
+	}
+
+	if appError.RetryAfter() > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(appError.RetryAfter())})
+	}
+
+	return withDetails(st, details...)
+}
+
+// Code returns the gRPC code of the error type
+func Code(errType app.ErrorType) codes.Code {
+	switch errType {
+	case app.ErrorValidation:
+		return codes.InvalidArgument
+	case app.ErrorNotFound:
+		return codes.NotFound
+	case app.ErrorPermission:
+		return codes.PermissionDenied
+	case app.ErrorUnauthorised:
+		return codes.Unauthenticated
+	case app.ErrorConflict:
+		return codes.AlreadyExists
+	case app.ErrorTimeout:
+		return codes.DeadlineExceeded
+	case app.ErrorCancelled:
+		return codes.Canceled
+	case app.ErrorUnavailable:
+		return codes.Unavailable
+	case app.ErrorRateLimited:
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
+
+// Type returns the error type of the gRPC code
+func Type(code codes.Code) app.ErrorType {
+	switch code {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return app.ErrorValidation
+	case codes.NotFound:
+		return app.ErrorNotFound
+	case codes.PermissionDenied:
+		return app.ErrorPermission
+	case codes.Unauthenticated:
+		return app.ErrorUnauthorised
+	case codes.AlreadyExists, codes.Aborted:
+		return app.ErrorConflict
+	case codes.DeadlineExceeded:
+		return app.ErrorTimeout
+	case codes.Canceled:
+		return app.ErrorCancelled
+	case codes.Unavailable:
+		return app.ErrorUnavailable
+	case codes.ResourceExhausted:
+		return app.ErrorRateLimited
+	default:
+		return app.ErrorInternal
+	}
+}
+
+// FromError converts a gRPC status error into an app.Error, it returns nil when the error is nil.
+// The error code is the ErrorInfo reason and the ErrorInfo metadata, including the remote trace id,
+// becomes the error metadata. The status error is the cause of the error.
+func FromError(err error) *app.Error {
+	if err == nil {
+		return nil
+	}
+
+	return FromStatus(status.Convert(err))
+}
+
+// FromStatus converts a gRPC status into an app.Error, it returns nil when the status is OK
+func FromStatus(st *status.Status) *app.Error {
+	if st.Code() == codes.OK {
+		return nil
+	}
+
+	errType := Type(st.Code())
+	appErr := app.NewError(reason(st), errType, app.DefaultSeverity(errType), st.Message()).Wrap(st.Err())
+
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			for key, value := range d.GetMetadata() {
+				appErr.SetMetadata(key, value)
+			}
+		case *errdetails.BadRequest:
+			for _, violation := range d.GetFieldViolations() {
+				appErr.AddValidationError(app.NewFieldValidationError(violation.GetField(), violation.GetDescription()))
+			}
+		case *errdetails.RetryInfo:
+			appErr.SetRetryAfter(d.GetRetryDelay().AsDuration())
+		}
+	}
+
+	return appErr
+}
+
+// reason returns the ErrorInfo reason or the gRPC code name like not_found
+func reason(st *status.Status) string {
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetReason() != "" {
+			return info.GetReason()
+		}
+	}
+
+	return snakeCase(st.Code().String())
+}
+
+// withDetails attaches the details to the status, the status is returned without details when they cannot be encoded
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) *status.Status {
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		return withDetails
+	}
+
+	return st
+}
+
+// fieldViolations converts the validation errors into BadRequest field violations sorted by field
+func fieldViolations(errs app.FieldValidationErrors) []*errdetails.BadRequest_FieldViolation {
+	fields := make([]string, 0, len(errs))
+
+	for field := range errs {
+		fields = append(fields, field)
+	}
+
+	sort.Strings(fields)
+
+	var violations []*errdetails.BadRequest_FieldViolation
+
+	for _, field := range fields {
+		for _, description := range errs[field] {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: field, Description: description})
+		}
+	}
+
+	return violations
+}
+
+// snakeCase converts the gRPC code name like NotFound into not_found
+func snakeCase(name string) string {
+	var b strings.Builder
+
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+
+			r = unicode.ToLower(r)
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,188 @@
+package grpcerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Talento90/goliath/app"
+)
+
+func newAppContext(traceID string) app.Context {
+	appCtx := app.FromContext(context.Background())
+	appCtx.SetTraceID(traceID)
+
+	return appCtx
+}
+
+func TestCodeMapping(t *testing.T) {
+	tt := []struct {
+		errType      app.ErrorType
+		expectedCode codes.Code
+	}{
+		{errType: app.ErrorValidation, expectedCode: codes.InvalidArgument},
+		{errType: app.ErrorNotFound, expectedCode: codes.NotFound},
+		{errType: app.ErrorPermission, expectedCode: codes.PermissionDenied},
+		{errType: app.ErrorUnauthorised, expectedCode: codes.Unauthenticated},
+		{errType: app.ErrorConflict, expectedCode: codes.AlreadyExists},
+		{errType: app.ErrorTimeout, expectedCode: codes.DeadlineExceeded},
+		{errType: app.ErrorCancelled, expectedCode: codes.Canceled},
+		{errType: app.ErrorUnavailable, expectedCode: codes.Unavailable},
+		{errType: app.ErrorRateLimited, expectedCode: codes.ResourceExhausted},
+		{errType: app.ErrorInternal, expectedCode: codes.Internal},
+		{errType: app.ErrorType("unknown"), expectedCode: codes.Internal},
+	}
+
+	for _, tc := range tt {
+		t.Run(string(tc.errType), func(t *testing.T) {
+			require.Equal(t, tc.expectedCode, Code(tc.errType))
+
+			if tc.errType != "unknown" {
+				require.Equal(t, tc.errType, Type(tc.expectedCode))
+			}
+		})
+	}
+}
+
+func TestTypeMapping(t *testing.T) {
+	require.Equal(t, app.ErrorValidation, Type(codes.FailedPrecondition))
+	require.Equal(t, app.ErrorValidation, Type(codes.OutOfRange))
+	require.Equal(t, app.ErrorConflict, Type(codes.Aborted))
+	require.Equal(t, app.ErrorInternal, Type(codes.Unknown))
+	require.Equal(t, app.ErrorInternal, Type(codes.DataLoss))
+}
+
+func TestNewWithValidationErrors(t *testing.T) {
+	err := app.NewErrorValidation("invalid_payment_data", "The payment request is invalid")
+	err.AddValidationError(app.NewFieldValidationError("currency", "currency is required"))
+	err.AddValidationError(app.NewFieldValidationError("amount", "Amount needs to be positive"))
+
+	st := New(newAppContext("trace-1"), err, WithDomain("payments.example.com"))
+
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Equal(t, "The payment request is invalid", st.Message())
+
+	details := st.Details()
+	require.Len(t, details, 3)
+
+	info, ok := details[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	require.Equal(t, "invalid_payment_data", info.GetReason())
+	require.Equal(t, "payments.example.com", info.GetDomain())
+	require.Equal(t, map[string]string{TraceIDKey: "trace-1", TypeKey: "validation"}, info.GetMetadata())
+
+	request, ok := details[1].(*errdetails.RequestInfo)
+	require.True(t, ok)
+	require.Equal(t, "trace-1", request.GetRequestId())
+
+	badRequest, ok := details[2].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Len(t, badRequest.GetFieldViolations(), 2)
+	require.Equal(t, "amount", badRequest.GetFieldViolations()[0].GetField())
+	require.Equal(t, "Amount needs to be positive", badRequest.GetFieldViolations()[0].GetDescription())
+	require.Equal(t, "currency", badRequest.GetFieldViolations()[1].GetField())
+}
+
+func TestNewWithRetryAfter(t *testing.T) {
+	err := app.NewErrorRateLimited("too_many_requests", "Too many requests").SetRetryAfter(2 * time.Second)
+
+	st := New(newAppContext("trace-1"), err)
+
+	require.Equal(t, codes.ResourceExhausted, st.Code())
+
+	retryInfo, ok := st.Details()[2].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	require.Equal(t, 2*time.Second, retryInfo.GetRetryDelay().AsDuration())
+}
+
+func TestNewHidesInternalMessages(t *testing.T) {
+	appCtx := newAppContext("trace-1")
+
+	st := New(appCtx, app.NewErrorInternal("database_error", `pq: relation "payments" does not exist`))
+	require.Equal(t, codes.Internal, st.Code())
+	require.Equal(t, app.GenericMessage, st.Message())
+
+	st = New(appCtx, app.NewErrorConflict("conflict", "duplicate key").SetSeverity(app.ErrorSeverityCritical))
+	require.Equal(t, app.GenericMessage, st.Message())
+
+	st = New(appCtx, app.NewErrorInternal("database_error", "pq: error").SetPublicMessage("The payment could not be saved"))
+	require.Equal(t, "The payment could not be saved", st.Message())
+}
+
+func TestNewWithExposurePolicy(t *testing.T) {
+	appCtx := newAppContext("trace-1")
+	err := app.NewErrorInternal("database_error", "pq: connection refused")
+
+	st := New(appCtx, err, WithExposurePolicy(func(*app.Error) bool { return true }))
+	require.Equal(t, "pq: connection refused", st.Message())
+
+	st = New(appCtx, app.NewErrorNotFound("payment_not_found", "Payment not found"), WithExposurePolicy(func(*app.Error) bool { return false }))
+	require.Equal(t, app.GenericMessage, st.Message())
+}
+
+func TestNewWithNonAppErrors(t *testing.T) {
+	appCtx := newAppContext("trace-1")
+
+	st := New(appCtx, errors.New("boom"))
+	require.Equal(t, codes.Internal, st.Code())
+	require.Equal(t, app.GenericMessage, st.Message())
+
+	request, ok := st.Details()[0].(*errdetails.RequestInfo)
+	require.True(t, ok)
+	require.Equal(t, "trace-1", request.GetRequestId())
+
+	st = New(appCtx, status.Error(codes.NotFound, "not found"))
+	require.Equal(t, codes.NotFound, st.Code())
+
+	st = New(appCtx, fmt.Errorf("query: %w", context.DeadlineExceeded))
+	require.Equal(t, codes.DeadlineExceeded, st.Code())
+
+	st = New(appCtx, context.Canceled)
+	require.Equal(t, codes.Canceled, st.Code())
+}
+
+func TestFromError(t *testing.T) {
+	require.Nil(t, FromError(nil))
+	require.Nil(t, FromStatus(status.New(codes.OK, "")))
+
+	err := app.NewErrorValidation("invalid_payment_data", "The payment request is invalid").SetRetryAfter(time.Second)
+	err.AddValidationError(app.NewFieldValidationError("amount", "Amount needs to be positive", "Amount is too big"))
+
+	appErr := FromError(New(newAppContext("trace-1"), err).Err())
+
+	require.Equal(t, "invalid_payment_data", appErr.Code())
+	require.Equal(t, app.ErrorValidation, appErr.Type())
+	require.Equal(t, app.ErrorSeverityLow, appErr.Severity())
+	require.Equal(t, "The payment request is invalid", appErr.Error())
+	require.Equal(t, time.Second, appErr.RetryAfter())
+	require.Equal(t, app.FieldValidationErrors{"amount": {"Amount needs to be positive", "Amount is too big"}}, appErr.ValidationErrors())
+	require.Equal(t, "trace-1", appErr.Metadata()[TraceIDKey])
+
+	st, ok := status.FromError(appErr.Cause())
+	require.True(t, ok)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+}
+
+func TestFromErrorWithoutDetails(t *testing.T) {
+	appErr := FromError(status.Error(codes.Unavailable, "service unavailable"))
+
+	require.Equal(t, "unavailable", appErr.Code())
+	require.Equal(t, app.ErrorUnavailable, appErr.Type())
+	require.Equal(t, app.ErrorSeverityMedium, appErr.Severity())
+	require.Equal(t, "service unavailable", appErr.Error())
+
+	appErr = FromError(status.Error(codes.NotFound, "not found"))
+	require.Equal(t, "not_found", appErr.Code())
+
+	appErr = FromError(errors.New("boom"))
+	require.Equal(t, "unknown", appErr.Code())
+	require.Equal(t, app.ErrorInternal, appErr.Type())
+	require.Equal(t, app.ErrorSeverityHigh, appErr.Severity())
+}
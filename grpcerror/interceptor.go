@@ -0,0 +1,88 @@
+package grpcerror
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc"
+
+	"github.com/Talento90/goliath/app"
+)
+
+// UnaryServerInterceptor converts the errors returned by the handlers into gRPC statuses
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+
+		if err != nil {
+			return resp, New(app.FromContext(ctx), err, opts...).Err()
+		}
+
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor converts the errors returned by the stream handlers into gRPC statuses
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return New(app.FromContext(ss.Context()), err, opts...).Err()
+		}
+
+		return nil
+	}
+}
+
+// UnaryClientInterceptor converts the gRPC statuses returned by the server into app errors
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := invoker(ctx, method, req, reply, cc, opts...); err != nil {
+			return FromError(err)
+		}
+
+		return nil
+	}
+}
+
+// StreamClientInterceptor converts the gRPC statuses returned by the server streams into app errors
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+
+		if err != nil {
+			return nil, FromError(err)
+		}
+
+		return &clientStream{ClientStream: stream}, nil
+	}
+}
+
+// clientStream converts the errors received from the server stream
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	if err := s.ClientStream.RecvMsg(m); err != nil {
+		return convert(err)
+	}
+
+	return nil
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	if err := s.ClientStream.SendMsg(m); err != nil {
+		return convert(err)
+	}
+
+	return nil
+}
+
+// convert converts the stream error keeping io.EOF that signals the end of the stream
+func convert(err error) error {
+	if err == io.EOF { //nolint:errorlint // grpc streams return io.EOF unwrapped
+		return err
+	}
+
+	return FromError(err)
+}
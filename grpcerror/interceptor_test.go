@@ -0,0 +1,132 @@
+package grpcerror
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Talento90/goliath/app"
+)
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s serverStream) Context() context.Context {
+	return s.ctx
+}
+
+type clientStreamStub struct {
+	grpc.ClientStream
+	err error
+}
+
+func (s clientStreamStub) RecvMsg(any) error {
+	return s.err
+}
+
+func (s clientStreamStub) SendMsg(any) error {
+	return s.err
+}
+
+func traceContext(traceID string) context.Context {
+	return context.WithValue(context.Background(), app.TraceIDKey, traceID)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor(WithDomain("payments"))
+
+	resp, err := interceptor(traceContext("trace-1"), "req", &grpc.UnaryServerInfo{}, func(context.Context, any) (any, error) {
+		return "resp", nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "resp", resp)
+
+	_, err = interceptor(traceContext("trace-1"), "req", &grpc.UnaryServerInfo{}, func(context.Context, any) (any, error) {
+		return nil, app.NewErrorNotFound("payment_not_found", "Payment not found")
+	})
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.NotFound, st.Code())
+	require.Equal(t, "Payment not found", st.Message())
+	require.Equal(t, "trace-1", FromStatus(st).Metadata()[TraceIDKey])
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	interceptor := StreamServerInterceptor()
+	stream := serverStream{ctx: traceContext("trace-2")}
+
+	err := interceptor(nil, stream, &grpc.StreamServerInfo{}, func(any, grpc.ServerStream) error {
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = interceptor(nil, stream, &grpc.StreamServerInfo{}, func(any, grpc.ServerStream) error {
+		return app.NewErrorPermission("forbidden", "Forbidden")
+	})
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.PermissionDenied, st.Code())
+	require.Equal(t, "trace-2", FromStatus(st).Metadata()[TraceIDKey])
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	interceptor := UnaryClientInterceptor()
+
+	err := interceptor(context.Background(), "/payments.Payments/Get", nil, nil, nil,
+		func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			return nil
+		})
+	require.NoError(t, err)
+
+	err = interceptor(context.Background(), "/payments.Payments/Get", nil, nil, nil,
+		func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			return New(app.FromContext(traceContext("trace-3")), app.NewErrorNotFound("payment_not_found", "Payment not found")).Err()
+		})
+
+	var appErr *app.Error
+	require.True(t, errors.As(err, &appErr))
+	require.Equal(t, "payment_not_found", appErr.Code())
+	require.Equal(t, app.ErrorNotFound, appErr.Type())
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	interceptor := StreamClientInterceptor()
+	streamErr := status.Error(codes.Unavailable, "unavailable")
+
+	_, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/payments.Payments/List",
+		func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+			return nil, streamErr
+		})
+
+	var appErr *app.Error
+	require.True(t, errors.As(err, &appErr))
+	require.Equal(t, app.ErrorUnavailable, appErr.Type())
+
+	stream, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/payments.Payments/List",
+		func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+			return clientStreamStub{err: streamErr}, nil
+		})
+	require.NoError(t, err)
+
+	require.True(t, errors.As(stream.RecvMsg(nil), &appErr))
+	require.True(t, errors.As(stream.SendMsg(nil), &appErr))
+
+	stream, err = interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/payments.Payments/List",
+		func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+			return clientStreamStub{err: io.EOF}, nil
+		})
+	require.NoError(t, err)
+
+	require.Equal(t, io.EOF, stream.RecvMsg(nil))
+}
@@ -0,0 +1,98 @@
+package httperror
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// members are the problem details members that extensions cannot override
+var members = map[string]bool{
+	"type":     true,
+	"title":    true,
+	"detail":   true,
+	"status":   true,
+	"instance": true,
+	"traceId":  true,
+	"errors":   true,
+}
+
+// isMember reports whether the name is a problem details member
+func isMember(name string) bool {
+	return members[name]
+}
+
+// problemDetails has the ProblemDetails fields without its json methods
+type problemDetails ProblemDetails
+
+// MarshalJSON flattens the extensions into top level members, extensions named like a member are ignored
+func (pd ProblemDetails) MarshalJSON() ([]byte, error) {
+	body, err := json.Marshal(problemDetails(pd))
+
+	if err != nil {
+		return nil, err
+	}
+
+	extensions := make(map[string]any, len(pd.Extensions))
+
+	for name, value := range pd.Extensions {
+		if !isMember(name) {
+			extensions[name] = value
+		}
+	}
+
+	if len(extensions) == 0 {
+		return body, nil
+	}
+
+	ext, err := json.Marshal(extensions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.Equal(body, []byte("{}")) {
+		return ext, nil
+	}
+
+	// merge {"type":...} and {"ext":...} into {"type":...,"ext":...}
+	body = append(body[:len(body)-1], ',')
+
+	return append(body, ext[1:]...), nil
+}
+
+// UnmarshalJSON decodes the problem details capturing the unknown members as extensions
+func (pd *ProblemDetails) UnmarshalJSON(data []byte) error {
+	var problem problemDetails
+
+	if err := json.Unmarshal(data, &problem); err != nil {
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	for name, value := range raw {
+		if isMember(name) {
+			continue
+		}
+
+		var extension any
+
+		if err := json.Unmarshal(value, &extension); err != nil {
+			return err
+		}
+
+		if problem.Extensions == nil {
+			problem.Extensions = make(map[string]any)
+		}
+
+		problem.Extensions[name] = extension
+	}
+
+	*pd = ProblemDetails(problem)
+
+	return nil
+}
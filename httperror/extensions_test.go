@@ -0,0 +1,134 @@
+package httperror
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Talento90/goliath/app"
+)
+
+func TestProblemDetailsMarshalJSONExtensions(t *testing.T) {
+	tt := []struct {
+		name         string
+		problem      ProblemDetails
+		expectedJSON string
+	}{
+		{
+			name:         "without extensions",
+			problem:      ProblemDetails{Type: "payment_not_found", Status: 404},
+			expectedJSON: `{"type":"payment_not_found","status":404}`,
+		},
+		{
+			name: "with extensions",
+			problem: ProblemDetails{
+				Type:       "payment_not_found",
+				Status:     404,
+				Extensions: map[string]any{"paymentId": "123", "balance": 30},
+			},
+			expectedJSON: `{"type":"payment_not_found","status":404,"balance":30,"paymentId":"123"}`,
+		},
+		{
+			name:         "only extensions",
+			problem:      ProblemDetails{Extensions: map[string]any{"paymentId": "123"}},
+			expectedJSON: `{"paymentId":"123"}`,
+		},
+		{
+			name: "extensions cannot override members",
+			problem: ProblemDetails{
+				Type:       "payment_not_found",
+				Extensions: map[string]any{"type": "override", "status": 200},
+			},
+			expectedJSON: `{"type":"payment_not_found"}`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := json.Marshal(tc.problem)
+
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expectedJSON, string(body))
+		})
+	}
+}
+
+func TestProblemDetailsUnmarshalJSONExtensions(t *testing.T) {
+	var problem ProblemDetails
+
+	err := json.Unmarshal([]byte(`{
+		"type": "out_of_credit",
+		"title": "You do not have enough credit.",
+		"status": 403,
+		"balance": 30,
+		"accounts": ["/account/12345", "/account/67890"]
+	}`), &problem)
+
+	require.NoError(t, err)
+	require.Equal(t, "out_of_credit", problem.Type)
+	require.Equal(t, 403, problem.Status)
+	require.Equal(t, map[string]any{
+		"balance":  float64(30),
+		"accounts": []any{"/account/12345", "/account/67890"},
+	}, problem.Extensions)
+}
+
+func TestNewWithExtensions(t *testing.T) {
+	appCtx := app.FromContext(context.Background())
+	err := app.NewErrorConflict("out_of_credit", "You do not have enough credit.").
+		SetMetadata("balance", 30).
+		SetMetadata("sql", "SELECT * FROM accounts").
+		SetMetadata("title", "override")
+
+	problem := New(appCtx, err, "/payments")
+	require.Nil(t, problem.Extensions)
+
+	problem = New(appCtx, err, "/payments", WithExtensions("balance", "title", "missing"))
+	require.Equal(t, map[string]any{"balance": 30}, problem.Extensions)
+}
+
+func TestRenderExtensions(t *testing.T) {
+	problem := ProblemDetails{
+		Type:       "out_of_credit",
+		Status:     http.StatusForbidden,
+		Extensions: map[string]any{"balance": 30, "accounts": []string{"/account/1"}, "invalid name": true},
+	}
+
+	tt := []struct {
+		name         string
+		accept       string
+		expectedBody string
+	}{
+		{
+			name:   "xml",
+			accept: ContentTypeXML,
+			expectedBody: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<problem xmlns="urn:ietf:rfc:7807"><type>out_of_credit</type><status>403</status>` +
+				`<accounts>[&#34;/account/1&#34;]</accounts><balance>30</balance></problem>`,
+		},
+		{
+			name:   "text",
+			accept: "text/plain",
+			expectedBody: "403 Forbidden\n" +
+				"type: out_of_credit\n" +
+				"accounts: [\"/account/1\"]\n" +
+				"balance: 30\n" +
+				"invalid name: true\n",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept", tc.accept)
+			rec := httptest.NewRecorder()
+
+			require.NoError(t, Render(rec, req, problem))
+			require.Equal(t, tc.expectedBody, rec.Body.String())
+		})
+	}
+}
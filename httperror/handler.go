@@ -0,0 +1,144 @@
+package httperror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/Talento90/goliath/app"
+)
+
+// HandlerFunc is an http handler that returns an error
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Handler adapts a HandlerFunc into an http.Handler that renders the returned errors and panics as problem details
+// in the format negotiated with the Accept header
+type Handler struct {
+	// Handle serves the request
+	Handle HandlerFunc
+	// Logger logs the errors according to their severity, slog.Default is used when nil
+	Logger *slog.Logger
+	// Options used to build the problem details
+	Options []Option
+}
+
+// NewHandler returns a Handler using the default logger
+func NewHandler(handle HandlerFunc) Handler {
+	return Handler{Handle: handle}
+}
+
+// ServeHTTP calls the handler and writes the problem details when it returns an error or panics
+func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	appCtx := app.FromContext(r.Context())
+	r = r.WithContext(appCtx)
+	rw := &responseWriter{ResponseWriter: w}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			if rec == http.ErrAbortHandler { //nolint:errorlint // sentinel value used by net/http to abort the handler
+				panic(rec)
+			}
+
+			err := app.NewErrorInternal(UnknownErrorType, "panic while serving the request").
+				SetSeverity(app.ErrorSeverityCritical).
+				Wrap(fmt.Errorf("panic: %v\n%s", rec, debug.Stack()))
+
+			h.handleError(appCtx, rw, r, err)
+		}
+	}()
+
+	if err := h.Handle(rw, r); err != nil {
+		h.handleError(appCtx, rw, r, err)
+	}
+}
+
+// handleError logs the error and renders the problem details unless the response was already written
+func (h Handler) handleError(appCtx app.Context, w *responseWriter, r *http.Request, err error) {
+	problem := New(appCtx, err, r.URL.Path, h.Options...)
+
+	h.log(appCtx, r, problem, err)
+
+	if w.written {
+		return
+	}
+
+	if writeErr := Render(w, r, problem); writeErr != nil {
+		h.logger().ErrorContext(appCtx, "error writing problem details", "trace_id", problem.TraceID, "error", writeErr)
+	}
+}
+
+func (h Handler) logger() *slog.Logger {
+	if h.Logger != nil {
+		return h.Logger
+	}
+
+	return slog.Default()
+}
+
+func (h Handler) log(ctx context.Context, r *http.Request, problem ProblemDetails, err error) {
+	level := slog.LevelError
+	attrs := []slog.Attr{
+		slog.String("trace_id", problem.TraceID),
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.Int("status", problem.Status),
+		slog.String("error", err.Error()),
+	}
+
+	var appErr *app.Error
+
+	if errors.As(err, &appErr) {
+		level = severityLevel(appErr.Severity())
+		attrs = append(attrs,
+			slog.String("code", appErr.Code()),
+			slog.String("type", string(appErr.Type())),
+			slog.String("severity", string(appErr.Severity())),
+		)
+
+		if appErr.Cause() != nil {
+			attrs = append(attrs, slog.String("cause", appErr.Cause().Error()))
+		}
+
+		if len(appErr.StackTrace()) > 0 {
+			attrs = append(attrs, slog.String("stack_trace", appErr.StackTrace().String()))
+		}
+	}
+
+	h.logger().LogAttrs(ctx, level, "request failed", attrs...)
+}
+
+// severityLevel maps the error severity into a log level
+func severityLevel(severity app.ErrorSeverity) slog.Level {
+	switch severity {
+	case app.ErrorSeverityLow:
+		return slog.LevelInfo
+	case app.ErrorSeverityMedium:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// responseWriter tracks if the response was already written
+type responseWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *responseWriter) WriteHeader(statusCode int) {
+	w.written = true
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the original response writer for http.ResponseController
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
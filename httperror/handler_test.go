@@ -0,0 +1,271 @@
+package httperror
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Talento90/goliath/app"
+)
+
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+func TestHandlerWritesProblemDetails(t *testing.T) {
+	var logs bytes.Buffer
+
+	handler := Handler{
+		Logger: newTestLogger(&logs),
+		Handle: func(http.ResponseWriter, *http.Request) error {
+			return app.NewErrorNotFound("payment_not_found", "Payment not found")
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/payments/1", nil)
+	req = req.WithContext(context.WithValue(req.Context(), app.TraceIDKey, "trace-1"))
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Equal(t, ContentTypeJSON, rec.Header().Get("Content-Type"))
+
+	var problem ProblemDetails
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	require.Equal(t, "payment_not_found", problem.Type)
+	require.Equal(t, "Payment not found", problem.Title)
+	require.Equal(t, http.StatusNotFound, problem.Status)
+	require.Equal(t, "/payments/1", problem.Instance)
+	require.Equal(t, "trace-1", problem.TraceID)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+	require.Equal(t, "INFO", entry["level"])
+	require.Equal(t, "trace-1", entry["trace_id"])
+	require.Equal(t, "payment_not_found", entry["code"])
+}
+
+func TestHandlerWithoutError(t *testing.T) {
+	var logs bytes.Buffer
+
+	handler := Handler{
+		Logger: newTestLogger(&logs),
+		Handle: func(w http.ResponseWriter, _ *http.Request) error {
+			w.WriteHeader(http.StatusNoContent)
+			return nil
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/payments/1", nil))
+
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Empty(t, rec.Body.String())
+	require.Empty(t, logs.String())
+}
+
+func TestHandlerLogLevelBySeverity(t *testing.T) {
+	tt := []struct {
+		name          string
+		err           error
+		expectedLevel string
+	}{
+		{
+			name:          "low severity",
+			err:           app.NewErrorValidation("invalid", "invalid").SetSeverity(app.ErrorSeverityLow),
+			expectedLevel: "INFO",
+		},
+		{
+			name:          "medium severity",
+			err:           app.NewErrorUnavailable("unavailable", "unavailable"),
+			expectedLevel: "WARN",
+		},
+		{
+			name:          "high severity",
+			err:           app.NewErrorInternal("internal", "internal").SetSeverity(app.ErrorSeverityHigh),
+			expectedLevel: "ERROR",
+		},
+		{
+			name:          "critical severity",
+			err:           app.NewErrorInternal("internal", "internal").SetSeverity(app.ErrorSeverityCritical),
+			expectedLevel: "ERROR",
+		},
+		{
+			name:          "non app error",
+			err:           errors.New("boom"),
+			expectedLevel: "ERROR",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var logs bytes.Buffer
+
+			handler := Handler{
+				Logger: newTestLogger(&logs),
+				Handle: func(http.ResponseWriter, *http.Request) error {
+					return tc.err
+				},
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+			require.Equal(t, tc.expectedLevel, entry["level"])
+		})
+	}
+}
+
+func TestHandlerRecoversPanic(t *testing.T) {
+	var logs bytes.Buffer
+
+	handler := Handler{
+		Logger: newTestLogger(&logs),
+		Handle: func(http.ResponseWriter, *http.Request) error {
+			panic("unexpected")
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/payments", nil)
+	req = req.WithContext(context.WithValue(req.Context(), app.TraceIDKey, "trace-2"))
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.Equal(t, ContentTypeJSON, rec.Header().Get("Content-Type"))
+
+	var problem ProblemDetails
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	require.Equal(t, UnknownErrorType, problem.Type)
+	require.Equal(t, http.StatusInternalServerError, problem.Status)
+	require.Equal(t, "trace-2", problem.TraceID)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+	require.Equal(t, "ERROR", entry["level"])
+	require.Contains(t, entry["cause"], "panic: unexpected")
+}
+
+func TestHandlerRepanicsAbortHandler(t *testing.T) {
+	handler := NewHandler(func(http.ResponseWriter, *http.Request) error {
+		panic(http.ErrAbortHandler)
+	})
+
+	require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func TestHandlerDoesNotOverwriteWrittenResponse(t *testing.T) {
+	var logs bytes.Buffer
+
+	handler := Handler{
+		Logger: newTestLogger(&logs),
+		Handle: func(w http.ResponseWriter, _ *http.Request) error {
+			w.WriteHeader(http.StatusAccepted)
+			return errors.New("failed after writing")
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	require.Empty(t, rec.Body.String())
+	require.Contains(t, logs.String(), "failed after writing")
+}
+
+func TestWriteRetryAfter(t *testing.T) {
+	problem := New(app.FromContext(context.Background()),
+		app.NewErrorRateLimited("too_many_requests", "Too many requests").SetRetryAfter(1500*time.Millisecond), "/payments")
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, Write(rec, problem))
+
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Equal(t, "2", rec.Header().Get("Retry-After"))
+}
+
+func TestHandlerWithOptions(t *testing.T) {
+	handler := Handler{
+		Logger:  newTestLogger(&bytes.Buffer{}),
+		Options: []Option{WithMapper(NewMapper().RegisterType(app.ErrorTimeout, http.StatusGatewayTimeout))},
+		Handle: func(http.ResponseWriter, *http.Request) error {
+			return app.NewErrorTimeout("upstream_timeout", "Upstream timeout")
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Equal(t, http.StatusGatewayTimeout, rec.Code)
+}
+
+func TestHandlerNegotiatesFormat(t *testing.T) {
+	handler := Handler{
+		Logger: newTestLogger(&bytes.Buffer{}),
+		Handle: func(http.ResponseWriter, *http.Request) error {
+			return app.NewErrorNotFound("payment_not_found", "Payment not found")
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/payments/1", nil)
+	req.Header.Set("Accept", "application/xml")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Equal(t, ContentTypeXML, rec.Header().Get("Content-Type"))
+}
+
+func TestHandlerLogsHiddenMessage(t *testing.T) {
+	var logs bytes.Buffer
+
+	handler := Handler{
+		Logger: newTestLogger(&logs),
+		Handle: func(http.ResponseWriter, *http.Request) error {
+			return app.NewErrorInternal("database_error", `pq: relation "payments" does not exist`)
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/payments", nil))
+
+	require.NotContains(t, rec.Body.String(), "pq: relation")
+	require.Contains(t, logs.String(), "pq: relation")
+}
+
+func TestHandlerLogsStackTrace(t *testing.T) {
+	t.Cleanup(app.EnableStackTraces())
+
+	var logs bytes.Buffer
+
+	handler := Handler{
+		Logger: newTestLogger(&logs),
+		Handle: func(http.ResponseWriter, *http.Request) error {
+			return app.NewErrorConflict("payment_conflict", "Payment already exists")
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/payments", nil))
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+	require.Contains(t, entry["stack_trace"], "httperror.TestHandlerLogsStackTrace")
+
+	require.NotContains(t, rec.Body.String(), "TestHandlerLogsStackTrace")
+	require.NotContains(t, rec.Body.String(), "handler_test.go")
+}
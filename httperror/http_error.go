@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/Talento90/goliath/app"
 )
@@ -35,56 +36,161 @@ type ProblemDetails struct {
 	Instance string                    `json:"instance,omitempty"`
 	TraceID  string                    `json:"traceId,omitempty"`
 	Errors   app.FieldValidationErrors `json:"errors,omitempty"`
+	// Extensions are additional members flattened into the problem details document
+	Extensions map[string]any `json:"-"`
+
+	// delay sent in the Retry-After header
+	retryAfter time.Duration
+	// documentation link sent in the Link header
+	documentation string
 }
 
 func (pd ProblemDetails) Error() string {
 	return fmt.Sprintf("%s: %s", pd.Type, pd.Title)
 }
 
+// RetryAfter returns the delay the client should wait before trying again (Retry-After header)
+func (pd ProblemDetails) RetryAfter() time.Duration {
+	return pd.retryAfter
+}
+
+// SetRetryAfter sets the delay the client should wait before trying again (Retry-After header)
+func (pd *ProblemDetails) SetRetryAfter(retryAfter time.Duration) {
+	pd.retryAfter = retryAfter
+}
+
+// Documentation returns the link to the documentation of the problem type (Link header)
+func (pd ProblemDetails) Documentation() string {
+	return pd.documentation
+}
+
+// status returns the status code, 500 Internal Server Error when it is not set
+func (pd ProblemDetails) status() int {
+	if pd.Status == 0 {
+		return http.StatusInternalServerError
+	}
+
+	return pd.Status
+}
+
 const UnknownErrorType = "internal_error"
 
-func New(ctx app.Context, err error, instance string) ProblemDetails {
+// Option customises how the problem details are built
+type Option func(o *options)
+
+type options struct {
+	mapper     *Mapper
+	registry   *Registry
+	extensions []string
+	exposure   app.ExposurePolicy
+}
+
+// WithMapper maps the app errors into status codes using the mapper instead of the default mapping
+func WithMapper(mapper *Mapper) Option {
+	return func(o *options) {
+		o.mapper = mapper
+	}
+}
+
+// WithRegistry replaces the error codes with the problem types registered in the registry
+func WithRegistry(registry *Registry) Option {
+	return func(o *options) {
+		o.registry = registry
+	}
+}
+
+// WithExtensions exposes the app.Error metadata of the allowed keys as extension members,
+// metadata is never exposed by default so internal data is not leaked
+func WithExtensions(keys ...string) Option {
+	return func(o *options) {
+		o.extensions = append(o.extensions, keys...)
+	}
+}
+
+// WithExposurePolicy decides which error messages and details are exposed instead of the app.DefaultExposurePolicy
+func WithExposurePolicy(policy app.ExposurePolicy) Option {
+	return func(o *options) {
+		o.exposure = policy
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{mapper: defaultMapper, exposure: app.DefaultExposurePolicy}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.mapper == nil {
+		o.mapper = defaultMapper
+	}
+
+	if o.exposure == nil {
+		o.exposure = app.DefaultExposurePolicy
+	}
+
+	return o
+}
+
+// New builds the problem details of the error.
+// The message and detail of the errors hidden by the exposure policy are replaced by the public message or app.GenericMessage.
+func New(ctx app.Context, err error, instance string, opts ...Option) ProblemDetails {
+	o := newOptions(opts)
+
 	var appError *app.Error
 	ok := errors.As(err, &appError)
 
 	if !ok {
-		return ProblemDetails{
+		return o.build(ProblemDetails{
 			Type:     UnknownErrorType,
-			Title:    "An error occurred, please contact support.",
-			Status:   500,
+			Title:    app.GenericMessage,
+			Status:   http.StatusInternalServerError,
 			TraceID:  ctx.TraceID(),
 			Instance: instance,
-		}
+		})
 	}
 
-	return ProblemDetails{
-		Type:     appError.Code(),
-		Title:    appError.Error(),
-		Detail:   appError.Detail(),
-		Status:   mapAppErrorToHTTPStatusCode(*appError),
-		Instance: instance,
-		TraceID:  ctx.TraceID(),
-		Errors:   appError.ValidationErrors(),
+	title, detail := app.Expose(appError, o.exposure)
+
+	return o.build(ProblemDetails{
+		Type:       appError.Code(),
+		Title:      title,
+		Detail:     detail,
+		Status:     o.mapper.Status(appError),
+		Instance:   instance,
+		TraceID:    ctx.TraceID(),
+		Errors:     appError.ValidationErrors(),
+		Extensions: o.allowed(appError.Metadata()),
+		retryAfter: appError.RetryAfter(),
+	})
+}
+
+// allowed returns the metadata of the allowed extension keys
+func (o options) allowed(metadata map[string]any) map[string]any {
+	var extensions map[string]any
+
+	for _, key := range o.extensions {
+		value, ok := metadata[key]
+
+		if !ok || isMember(key) {
+			continue
+		}
+
+		if extensions == nil {
+			extensions = make(map[string]any)
+		}
+
+		extensions[key] = value
 	}
+
+	return extensions
 }
 
-func mapAppErrorToHTTPStatusCode(appError app.Error) int {
-	switch appError.Type() {
-	case app.ErrorValidation:
-		return http.StatusBadRequest
-	case app.ErrorNotFound:
-		return http.StatusNotFound
-	case app.ErrorPermission:
-		return http.StatusForbidden
-	case app.ErrorUnauthorised:
-		return http.StatusUnauthorized
-	case app.ErrorConflict:
-		return http.StatusConflict
-	case app.ErrorTimeout:
-		return http.StatusRequestTimeout
-	case app.ErrorCancelled:
-		return http.StatusAccepted
-	default:
-		return http.StatusInternalServerError
+// build applies the options to the problem details
+func (o options) build(pd ProblemDetails) ProblemDetails {
+	if o.registry != nil {
+		o.registry.apply(&pd)
 	}
+
+	return pd
 }
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -18,12 +19,19 @@ func TestNewProblemDetailWithapp(t *testing.T) {
 
 	httpErr := New(appCtx, err, "/payments")
 	require.Equal(t, err.Code(), httpErr.Type)
-	require.Equal(t, err.Error(), httpErr.Title)
-	require.Equal(t, err.Detail(), httpErr.Detail)
+	require.Equal(t, app.GenericMessage, httpErr.Title)
+	require.Equal(t, "", httpErr.Detail)
 	require.Equal(t, "/payments", httpErr.Instance)
 	require.Equal(t, 500, httpErr.Status)
 	require.Equal(t, appCtx.TraceID(), httpErr.TraceID)
 
+	require.Equal(t, "insuffient_funds: "+app.GenericMessage, httpErr.Error())
+
+	exposeAll := func(*app.Error) bool { return true }
+	httpErr = New(appCtx, err, "/payments", WithExposurePolicy(exposeAll))
+	require.Equal(t, err.Error(), httpErr.Title)
+	require.Equal(t, err.Detail(), httpErr.Detail)
+
 	require.Equal(t, "insuffient_funds: No funds available", httpErr.Error())
 }
 
@@ -73,6 +81,16 @@ func TestNewProblemDetailStatusCodeMapping(t *testing.T) {
 			errType:            app.ErrorValidation,
 			expectedStatusCode: http.StatusBadRequest,
 		},
+		{
+			name:               "map to too many requests",
+			errType:            app.ErrorRateLimited,
+			expectedStatusCode: http.StatusTooManyRequests,
+		},
+		{
+			name:               "map to service unavailable",
+			errType:            app.ErrorUnavailable,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
 	}
 
 	for _, tc := range tt {
@@ -124,3 +142,70 @@ func TestNewProblemDetailWithGenericError(t *testing.T) {
 	require.Equal(t, 500, httpErr.Status)
 	require.Equal(t, appCtx.TraceID(), httpErr.TraceID)
 }
+
+func TestNewProblemDetailWithRetryAfter(t *testing.T) {
+	appCtx := app.FromContext(context.Background())
+	err := app.NewErrorRateLimited("rate_limit_exceeded", "Too many requests").SetRetryAfter(2 * time.Second)
+
+	httpErr := New(appCtx, err, "/payments")
+
+	require.Equal(t, http.StatusTooManyRequests, httpErr.Status)
+	require.Equal(t, 2*time.Second, httpErr.RetryAfter())
+
+	httpErr.SetRetryAfter(time.Second)
+	require.Equal(t, time.Second, httpErr.RetryAfter())
+}
+
+func TestNewProblemDetailWithoutStackTrace(t *testing.T) {
+	t.Cleanup(app.EnableStackTraces())
+
+	err := app.NewErrorValidation("invalid_payment_data", "The payment request is invalid")
+	require.NotEmpty(t, err.StackTrace())
+
+	body, marshalErr := json.Marshal(New(app.FromContext(context.Background()), err, "/payments"))
+	require.NoError(t, marshalErr)
+
+	require.NotContains(t, string(body), "http_error_test.go")
+	require.NotContains(t, string(body), "TestNewProblemDetailWithoutStackTrace")
+}
+
+func TestNewHidesInternalErrors(t *testing.T) {
+	appCtx := app.FromContext(context.Background())
+
+	t.Run("generic title", func(t *testing.T) {
+		err := app.NewErrorInternal("database_error", `pq: relation "payments" does not exist`).SetDetail("SELECT * FROM payments")
+		problem := New(appCtx, err, "/payments")
+
+		require.Equal(t, "database_error", problem.Type)
+		require.Equal(t, app.GenericMessage, problem.Title)
+		require.Empty(t, problem.Detail)
+		require.Equal(t, appCtx.TraceID(), problem.TraceID)
+	})
+
+	t.Run("public message", func(t *testing.T) {
+		err := app.NewErrorInternal("database_error", `pq: relation "payments" does not exist`).
+			SetPublicMessage("The payment could not be saved").
+			SetDetail("SELECT * FROM payments")
+		problem := New(appCtx, err, "/payments")
+
+		require.Equal(t, "The payment could not be saved", problem.Title)
+		require.Empty(t, problem.Detail)
+	})
+
+	t.Run("exposed public message", func(t *testing.T) {
+		err := app.NewErrorNotFound("payment_not_found", "payment 1 not found in table payments").
+			SetPublicMessage("Payment not found").
+			SetDetail("The payment 1 does not exist")
+		problem := New(appCtx, err, "/payments/1")
+
+		require.Equal(t, "Payment not found", problem.Title)
+		require.Equal(t, "The payment 1 does not exist", problem.Detail)
+	})
+
+	t.Run("custom policy", func(t *testing.T) {
+		hideAll := func(*app.Error) bool { return false }
+		problem := New(appCtx, app.NewErrorNotFound("payment_not_found", "Payment not found"), "/payments/1", WithExposurePolicy(hideAll))
+
+		require.Equal(t, app.GenericMessage, problem.Title)
+	})
+}
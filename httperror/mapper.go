@@ -0,0 +1,120 @@
+package httperror
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/Talento90/goliath/app"
+)
+
+// Mapper maps app errors into http status codes and status codes back into error types.
+// Status codes registered for an error code take precedence over the ones registered for an error type.
+type Mapper struct {
+	mu       sync.RWMutex
+	types    map[app.ErrorType]int
+	codes    map[string]int
+	statuses map[int]app.ErrorType
+}
+
+// NewMapper returns a mapper with the default status code of every app.ErrorType
+func NewMapper() *Mapper {
+	return &Mapper{
+		types: map[app.ErrorType]int{
+			app.ErrorValidation:   http.StatusBadRequest,
+			app.ErrorNotFound:     http.StatusNotFound,
+			app.ErrorPermission:   http.StatusForbidden,
+			app.ErrorUnauthorised: http.StatusUnauthorized,
+			app.ErrorConflict:     http.StatusConflict,
+			app.ErrorTimeout:      http.StatusRequestTimeout,
+			app.ErrorCancelled:    http.StatusAccepted,
+			app.ErrorUnavailable:  http.StatusServiceUnavailable,
+			app.ErrorRateLimited:  http.StatusTooManyRequests,
+			app.ErrorInternal:     http.StatusInternalServerError,
+		},
+		codes: map[string]int{},
+		statuses: map[int]app.ErrorType{
+			http.StatusBadRequest:          app.ErrorValidation,
+			http.StatusUnprocessableEntity: app.ErrorValidation,
+			http.StatusNotFound:            app.ErrorNotFound,
+			http.StatusForbidden:           app.ErrorPermission,
+			http.StatusUnauthorized:        app.ErrorUnauthorised,
+			http.StatusConflict:            app.ErrorConflict,
+			http.StatusRequestTimeout:      app.ErrorTimeout,
+			http.StatusGatewayTimeout:      app.ErrorTimeout,
+			http.StatusAccepted:            app.ErrorCancelled,
+			http.StatusServiceUnavailable:  app.ErrorUnavailable,
+			http.StatusTooManyRequests:     app.ErrorRateLimited,
+			http.StatusInternalServerError: app.ErrorInternal,
+		},
+	}
+}
+
+// RegisterType registers or overrides the status code of an error type.
+// The status code is also mapped back into the error type unless it is already registered.
+func (m *Mapper) RegisterType(errType app.ErrorType, status int) *Mapper {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.types[errType] = status
+
+	if _, ok := m.statuses[status]; !ok {
+		m.statuses[status] = errType
+	}
+
+	return m
+}
+
+// RegisterStatus registers or overrides the error type of a status code
+func (m *Mapper) RegisterStatus(status int, errType app.ErrorType) *Mapper {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.statuses[status] = errType
+
+	return m
+}
+
+// RegisterCode registers or overrides the status code of an error code
+func (m *Mapper) RegisterCode(code string, status int) *Mapper {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.codes[code] = status
+
+	return m
+}
+
+// Status returns the status code of the error, 500 Internal Server Error when it is not registered
+func (m *Mapper) Status(appError *app.Error) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if status, ok := m.codes[appError.Code()]; ok {
+		return status
+	}
+
+	if status, ok := m.types[appError.Type()]; ok {
+		return status
+	}
+
+	return http.StatusInternalServerError
+}
+
+// Type returns the error type of the status code.
+// Unregistered client errors are validation errors and unregistered server errors are internal errors.
+func (m *Mapper) Type(status int) app.ErrorType {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if errType, ok := m.statuses[status]; ok {
+		return errType
+	}
+
+	if status >= http.StatusBadRequest && status < http.StatusInternalServerError {
+		return app.ErrorValidation
+	}
+
+	return app.ErrorInternal
+}
+
+var defaultMapper = NewMapper()
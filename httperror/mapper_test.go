@@ -0,0 +1,85 @@
+package httperror
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Talento90/goliath/app"
+)
+
+func TestMapperStatus(t *testing.T) {
+	mapper := NewMapper().
+		RegisterType(app.ErrorTimeout, http.StatusGatewayTimeout).
+		RegisterType(app.ErrorCancelled, 499).
+		RegisterCode("payment_expired", http.StatusGone)
+
+	tt := []struct {
+		name               string
+		err                *app.Error
+		expectedStatusCode int
+	}{
+		{
+			name:               "default type mapping",
+			err:                app.NewErrorNotFound("payment_not_found", "not found"),
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "overridden type",
+			err:                app.NewErrorTimeout("gateway_timeout", "timeout"),
+			expectedStatusCode: http.StatusGatewayTimeout,
+		},
+		{
+			name:               "custom status code",
+			err:                app.NewErrorCancelled("cancelled", "cancelled"),
+			expectedStatusCode: 499,
+		},
+		{
+			name:               "code takes precedence over type",
+			err:                app.NewErrorValidation("payment_expired", "expired"),
+			expectedStatusCode: http.StatusGone,
+		},
+		{
+			name:               "unknown type",
+			err:                app.NewError("unknown", app.ErrorType("unknown"), app.ErrorSeverityLow, "unknown"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedStatusCode, mapper.Status(tc.err))
+		})
+	}
+}
+
+func TestNewMapperDoesNotChangeDefaultMapping(t *testing.T) {
+	NewMapper().RegisterType(app.ErrorTimeout, http.StatusGatewayTimeout)
+
+	problem := New(app.FromContext(context.Background()), app.NewErrorTimeout("timeout", "timeout"), "/payments")
+
+	require.Equal(t, http.StatusRequestTimeout, problem.Status)
+}
+
+func TestNewWithMapper(t *testing.T) {
+	mapper := NewMapper().RegisterType(app.ErrorTimeout, http.StatusGatewayTimeout)
+
+	problem := New(app.FromContext(context.Background()), app.NewErrorTimeout("timeout", "timeout"), "/payments", WithMapper(mapper))
+
+	require.Equal(t, http.StatusGatewayTimeout, problem.Status)
+}
+
+func TestMapperType(t *testing.T) {
+	mapper := NewMapper().
+		RegisterType(app.ErrorTimeout, 599).
+		RegisterType(app.ErrorCancelled, http.StatusInternalServerError).
+		RegisterStatus(http.StatusGone, app.ErrorNotFound)
+
+	require.Equal(t, app.ErrorTimeout, mapper.Type(599))
+	require.Equal(t, app.ErrorInternal, mapper.Type(http.StatusInternalServerError))
+	require.Equal(t, app.ErrorNotFound, mapper.Type(http.StatusGone))
+	require.Equal(t, app.ErrorValidation, mapper.Type(http.StatusTeapot))
+	require.Equal(t, app.ErrorInternal, mapper.Type(http.StatusBadGateway))
+}
@@ -0,0 +1,134 @@
+package httperror
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// AboutBlank is the problem type used when the problem has no additional semantics beyond the status code
+const AboutBlank = "about:blank"
+
+// CodeExtension is the extension member keeping the error code of problems that fall back to "about:blank"
+const CodeExtension = "code"
+
+// ProblemType documents a problem identified by an error code
+type ProblemType struct {
+	// URI identifying the problem type, absolute or relative to the registry base URI.
+	// The error code relative to the base URI is used when empty.
+	URI string
+	// Title is the stable summary of the problem type, the error message is moved to the detail when it is set
+	Title string
+	// Documentation is an optional link to human-readable documentation sent in the Link header
+	Documentation string
+}
+
+// Registry maps error codes into problem types.
+// Errors without a registered problem type fall back to "about:blank" with the status text as title
+// and keep their error code in the CodeExtension member.
+type Registry struct {
+	mu      sync.RWMutex
+	baseURI string
+	types   map[string]ProblemType
+}
+
+// NewRegistry returns an empty registry resolving the type URIs against the base URI (e.g. https://errors.example.com/)
+func NewRegistry(baseURI string) *Registry {
+	return &Registry{
+		baseURI: baseURI,
+		types:   map[string]ProblemType{},
+	}
+}
+
+// Register registers or overrides the problem type of an error code
+func (r *Registry) Register(code string, problemType ProblemType) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.types[code] = problemType
+
+	return r
+}
+
+// Lookup returns the problem type of the error code with its URI resolved against the base URI
+func (r *Registry) Lookup(code string) (ProblemType, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	problemType, ok := r.types[code]
+
+	if !ok {
+		return ProblemType{}, false
+	}
+
+	problemType.URI = r.resolve(code, problemType.URI)
+
+	return problemType, true
+}
+
+// Code returns the error code registered with the type URI
+func (r *Registry) Code(uri string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for code, problemType := range r.types {
+		if r.resolve(code, problemType.URI) == uri {
+			return code, true
+		}
+	}
+
+	return "", false
+}
+
+// resolve returns the absolute type URI, must be called holding the lock
+func (r *Registry) resolve(code string, uri string) string {
+	if uri == "" {
+		uri = url.PathEscape(code)
+	}
+
+	if u, err := url.Parse(uri); err == nil && u.IsAbs() {
+		return uri
+	}
+
+	if r.baseURI == "" {
+		return uri
+	}
+
+	return strings.TrimSuffix(r.baseURI, "/") + "/" + strings.TrimPrefix(uri, "/")
+}
+
+// apply replaces the error code of the problem details with its registered problem type
+func (r *Registry) apply(pd *ProblemDetails) {
+	problemType, ok := r.Lookup(pd.Type)
+
+	if !ok {
+		if pd.Detail == "" {
+			pd.Detail = pd.Title
+		}
+
+		if pd.Type != "" && pd.Type != AboutBlank {
+			if pd.Extensions == nil {
+				pd.Extensions = make(map[string]any)
+			}
+
+			pd.Extensions[CodeExtension] = pd.Type
+		}
+
+		pd.Type = AboutBlank
+		pd.Title = http.StatusText(pd.Status)
+
+		return
+	}
+
+	if problemType.Title != "" {
+		if pd.Detail == "" {
+			pd.Detail = pd.Title
+		}
+
+		pd.Title = problemType.Title
+	}
+
+	pd.Type = problemType.URI
+	pd.documentation = problemType.Documentation
+}
@@ -0,0 +1,130 @@
+package httperror
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Talento90/goliath/app"
+)
+
+func TestRegistryLookup(t *testing.T) {
+	registry := NewRegistry("https://errors.example.com/").
+		Register("payment_not_found", ProblemType{Title: "Payment not found"}).
+		Register("insufficient_funds", ProblemType{URI: "/payments/insufficient-funds"}).
+		Register("invalid_card", ProblemType{URI: "https://cards.example.com/invalid"})
+
+	tt := []struct {
+		name        string
+		code        string
+		expectedURI string
+		expectedOk  bool
+	}{
+		{
+			name:        "code relative to base uri",
+			code:        "payment_not_found",
+			expectedURI: "https://errors.example.com/payment_not_found",
+			expectedOk:  true,
+		},
+		{
+			name:        "relative uri",
+			code:        "insufficient_funds",
+			expectedURI: "https://errors.example.com/payments/insufficient-funds",
+			expectedOk:  true,
+		},
+		{
+			name:        "absolute uri",
+			code:        "invalid_card",
+			expectedURI: "https://cards.example.com/invalid",
+			expectedOk:  true,
+		},
+		{
+			name:       "not registered",
+			code:       "unknown",
+			expectedOk: false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			problemType, ok := registry.Lookup(tc.code)
+
+			require.Equal(t, tc.expectedOk, ok)
+			require.Equal(t, tc.expectedURI, problemType.URI)
+		})
+	}
+}
+
+func TestRegistryWithoutBaseURI(t *testing.T) {
+	registry := NewRegistry("").Register("payment_not_found", ProblemType{})
+
+	problemType, ok := registry.Lookup("payment_not_found")
+
+	require.True(t, ok)
+	require.Equal(t, "payment_not_found", problemType.URI)
+}
+
+func TestNewWithRegistry(t *testing.T) {
+	registry := NewRegistry("https://errors.example.com").
+		Register("payment_not_found", ProblemType{
+			Title:         "Payment not found",
+			Documentation: "https://docs.example.com/errors/payment_not_found",
+		}).
+		Register("insufficient_funds", ProblemType{})
+
+	appCtx := app.FromContext(context.Background())
+
+	t.Run("registered with title", func(t *testing.T) {
+		problem := New(appCtx, app.NewErrorNotFound("payment_not_found", "Payment 1 does not exist"), "/payments/1", WithRegistry(registry))
+
+		require.Equal(t, "https://errors.example.com/payment_not_found", problem.Type)
+		require.Equal(t, "Payment not found", problem.Title)
+		require.Equal(t, "Payment 1 does not exist", problem.Detail)
+		require.Equal(t, http.StatusNotFound, problem.Status)
+		require.Equal(t, "https://docs.example.com/errors/payment_not_found", problem.Documentation())
+	})
+
+	t.Run("registered without title", func(t *testing.T) {
+		err := app.NewErrorConflict("insufficient_funds", "No funds available").SetDetail("Balance is 0")
+		problem := New(appCtx, err, "/payments", WithRegistry(registry))
+
+		require.Equal(t, "https://errors.example.com/insufficient_funds", problem.Type)
+		require.Equal(t, "No funds available", problem.Title)
+		require.Equal(t, "Balance is 0", problem.Detail)
+		require.Empty(t, problem.Documentation())
+	})
+
+	t.Run("not registered", func(t *testing.T) {
+		problem := New(appCtx, app.NewErrorValidation("invalid_amount", "Amount needs to be positive"), "/payments", WithRegistry(registry))
+
+		require.Equal(t, AboutBlank, problem.Type)
+		require.Equal(t, http.StatusText(http.StatusBadRequest), problem.Title)
+		require.Equal(t, "Amount needs to be positive", problem.Detail)
+		require.Equal(t, "invalid_amount", problem.Extensions[CodeExtension])
+	})
+
+	t.Run("non app error", func(t *testing.T) {
+		problem := New(appCtx, errors.New("boom"), "/payments", WithRegistry(registry))
+
+		require.Equal(t, AboutBlank, problem.Type)
+		require.Equal(t, http.StatusText(http.StatusInternalServerError), problem.Title)
+		require.Equal(t, http.StatusInternalServerError, problem.Status)
+		require.Equal(t, UnknownErrorType, problem.Extensions[CodeExtension])
+	})
+}
+
+func TestWriteDocumentationLink(t *testing.T) {
+	registry := NewRegistry("https://errors.example.com/").
+		Register("payment_not_found", ProblemType{Documentation: "https://docs.example.com/errors/payment_not_found"})
+
+	problem := New(app.FromContext(context.Background()), app.NewErrorNotFound("payment_not_found", "not found"), "/payments/1", WithRegistry(registry))
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, Write(rec, problem))
+
+	require.Equal(t, `<https://docs.example.com/errors/payment_not_found>; rel="help"`, rec.Header().Get("Link"))
+}
@@ -0,0 +1,242 @@
+package httperror
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ContentTypeJSON is the media type of the problem details JSON document
+	ContentTypeJSON = "application/problem+json"
+	// ContentTypeXML is the media type of the problem details XML document
+	ContentTypeXML = "application/problem+xml"
+	// ContentTypeText is the media type of the plain text fallback
+	ContentTypeText = "text/plain; charset=utf-8"
+)
+
+// format of the rendered problem details
+type format int
+
+const (
+	formatJSON format = iota
+	formatXML
+	formatText
+)
+
+// offers are the media types accepted by Render in order of preference
+var offers = []struct {
+	mediaType string
+	format    format
+}{
+	{mediaType: ContentTypeJSON, format: formatJSON},
+	{mediaType: "application/json", format: formatJSON},
+	{mediaType: ContentTypeXML, format: formatXML},
+	{mediaType: "application/xml", format: formatXML},
+	{mediaType: "text/xml", format: formatXML},
+	{mediaType: "text/plain", format: formatText},
+}
+
+// Write writes the problem details as application/problem+json with its status code, Retry-After and Link headers
+func Write(w http.ResponseWriter, problem ProblemDetails) error {
+	return write(w, formatJSON, problem)
+}
+
+// Render writes the problem details in the format negotiated with the request Accept header:
+// application/problem+json, application/problem+xml or text/plain.
+// JSON is used when the header is missing and plain text when no format is acceptable.
+func Render(w http.ResponseWriter, r *http.Request, problem ProblemDetails) error {
+	w.Header().Add("Vary", "Accept")
+
+	return write(w, negotiate(r.Header.Get("Accept")), problem)
+}
+
+func write(w http.ResponseWriter, f format, problem ProblemDetails) error {
+	var (
+		body        []byte
+		contentType string
+		err         error
+	)
+
+	switch f {
+	case formatXML:
+		contentType = ContentTypeXML
+		body, err = xml.Marshal(problem)
+		body = append([]byte(xml.Header), body...)
+	case formatText:
+		contentType = ContentTypeText
+		body = marshalText(problem)
+	default:
+		contentType = ContentTypeJSON
+		body, err = json.Marshal(problem)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", contentType)
+
+	if problem.RetryAfter() > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(problem.RetryAfter().Seconds()))))
+	}
+
+	if problem.Documentation() != "" {
+		w.Header().Set("Link", "<"+problem.Documentation()+`>; rel="help"`)
+	}
+
+	w.WriteHeader(problem.status())
+	_, err = w.Write(body)
+
+	return err
+}
+
+// negotiate returns the format with the highest quality in the Accept header
+func negotiate(accept string) format {
+	if strings.TrimSpace(accept) == "" {
+		return formatJSON
+	}
+
+	ranges := parseAccept(accept)
+	best, bestQuality := formatText, 0.0
+
+	for _, offer := range offers {
+		if q := quality(ranges, offer.mediaType); q > bestQuality {
+			best, bestQuality = offer.format, q
+		}
+	}
+
+	return best
+}
+
+// acceptRange is a media range of the Accept header
+type acceptRange struct {
+	mediaType string
+	quality   float64
+}
+
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+
+	for _, value := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(value))
+
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		ranges = append(ranges, acceptRange{mediaType: mediaType, quality: q})
+	}
+
+	return ranges
+}
+
+// quality returns the quality of the most specific range matching the media type
+func quality(ranges []acceptRange, mediaType string) float64 {
+	mainType, _, _ := strings.Cut(mediaType, "/")
+	q, specificity := 0.0, -1
+
+	for _, r := range ranges {
+		s := -1
+
+		switch r.mediaType {
+		case mediaType:
+			s = 2
+		case mainType + "/*":
+			s = 1
+		case "*/*":
+			s = 0
+		}
+
+		if s > specificity {
+			q, specificity = r.quality, s
+		}
+	}
+
+	return q
+}
+
+// marshalText renders the problem details as lines of "member: value"
+func marshalText(problem ProblemDetails) []byte {
+	var b strings.Builder
+
+	status := problem.status()
+	fmt.Fprintf(&b, "%d %s\n", status, http.StatusText(status))
+
+	members := []struct {
+		name  string
+		value string
+	}{
+		{name: "type", value: problem.Type},
+		{name: "title", value: problem.Title},
+		{name: "detail", value: problem.Detail},
+		{name: "instance", value: problem.Instance},
+		{name: "traceId", value: problem.TraceID},
+	}
+
+	for _, m := range members {
+		if m.value != "" {
+			fmt.Fprintf(&b, "%s: %s\n", m.name, m.value)
+		}
+	}
+
+	for _, name := range sortedKeys(problem.Extensions) {
+		if isMember(name) {
+			continue
+		}
+
+		if value, err := extensionText(problem.Extensions[name]); err == nil {
+			fmt.Fprintf(&b, "%s: %s\n", name, value)
+		}
+	}
+
+	if len(problem.Errors) > 0 {
+		b.WriteString("errors:\n")
+
+		for _, field := range sortedKeys(problem.Errors) {
+			for _, message := range problem.Errors[field] {
+				fmt.Fprintf(&b, "  %s: %s\n", field, message)
+			}
+		}
+	}
+
+	return []byte(b.String())
+}
+
+// sortedKeys returns the keys of the validation errors or extensions in a stable order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// extensionText returns the text of an extension value, values that are not strings are encoded as json
+func extensionText(value any) (string, error) {
+	if text, ok := value.(string); ok {
+		return text, nil
+	}
+
+	body, err := json.Marshal(value)
+
+	return string(body), err
+}
@@ -0,0 +1,115 @@
+package httperror
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Talento90/goliath/app"
+)
+
+func newValidationProblem() ProblemDetails {
+	appCtx := app.FromContext(context.Background())
+	appCtx.SetTraceID("trace-1")
+
+	err := app.NewErrorValidation("invalid_payment_data", "The payment request is invalid")
+	err.AddValidationError(app.NewFieldValidationError("currency", "currency is required"))
+	err.AddValidationError(app.NewFieldValidationError("amount", "Amount needs to be positive", "Amount is too big"))
+
+	return New(appCtx, err, "/payments")
+}
+
+func TestNegotiate(t *testing.T) {
+	tt := []struct {
+		name           string
+		accept         string
+		expectedFormat format
+	}{
+		{name: "missing header", accept: "", expectedFormat: formatJSON},
+		{name: "any media type", accept: "*/*", expectedFormat: formatJSON},
+		{name: "problem json", accept: "application/problem+json", expectedFormat: formatJSON},
+		{name: "json", accept: "application/json", expectedFormat: formatJSON},
+		{name: "problem xml", accept: "application/problem+xml", expectedFormat: formatXML},
+		{name: "xml", accept: "application/xml", expectedFormat: formatXML},
+		{name: "text xml", accept: "text/xml", expectedFormat: formatXML},
+		{name: "text", accept: "text/plain", expectedFormat: formatText},
+		{name: "quality", accept: "application/json;q=0.5, application/xml;q=0.9", expectedFormat: formatXML},
+		{name: "specific range wins", accept: "application/*;q=0.1, text/plain", expectedFormat: formatText},
+		{name: "excluded json", accept: "application/json;q=0, */*", expectedFormat: formatJSON},
+		{name: "browser", accept: "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", expectedFormat: formatXML},
+		{name: "not acceptable", accept: "text/html", expectedFormat: formatText},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedFormat, negotiate(tc.accept))
+		})
+	}
+}
+
+func TestRenderJSON(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/payments", nil)
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, Render(rec, req, newValidationProblem()))
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Equal(t, ContentTypeJSON, rec.Header().Get("Content-Type"))
+	require.Equal(t, "Accept", rec.Header().Get("Vary"))
+
+	var problem ProblemDetails
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	require.Equal(t, app.FieldValidationErrors{
+		"amount":   {"Amount needs to be positive", "Amount is too big"},
+		"currency": {"currency is required"},
+	}, problem.Errors)
+}
+
+func TestRenderXML(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/payments", nil)
+	req.Header.Set("Accept", ContentTypeXML)
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, Render(rec, req, newValidationProblem()))
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Equal(t, ContentTypeXML, rec.Header().Get("Content-Type"))
+	require.Equal(t, xml.Header+
+		`<problem xmlns="urn:ietf:rfc:7807">`+
+		`<type>invalid_payment_data</type>`+
+		`<title>The payment request is invalid</title>`+
+		`<status>400</status>`+
+		`<instance>/payments</instance>`+
+		`<traceId>trace-1</traceId>`+
+		`<errors>`+
+		`<field name="amount"><i>Amount needs to be positive</i><i>Amount is too big</i></field>`+
+		`<field name="currency"><i>currency is required</i></field>`+
+		`</errors>`+
+		`</problem>`, rec.Body.String())
+}
+
+func TestRenderText(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/payments", nil)
+	req.Header.Set("Accept", "text/html")
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, Render(rec, req, newValidationProblem()))
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Equal(t, ContentTypeText, rec.Header().Get("Content-Type"))
+	require.Equal(t, "400 Bad Request\n"+
+		"type: invalid_payment_data\n"+
+		"title: The payment request is invalid\n"+
+		"instance: /payments\n"+
+		"traceId: trace-1\n"+
+		"errors:\n"+
+		"  amount: Amount needs to be positive\n"+
+		"  amount: Amount is too big\n"+
+		"  currency: currency is required\n", rec.Body.String())
+}
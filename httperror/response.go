@@ -0,0 +1,129 @@
+package httperror
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Talento90/goliath/app"
+)
+
+// maxResponseSize limits the size of the problem details read from a response
+const maxResponseSize = 1 << 20
+
+// FromResponse converts an error response into an app.Error, it returns nil when the status code is not an error.
+// The error type is the inverse of the status code mapping and the error code is the problem type,
+// resolved back into the error code when a registry is used.
+// Extension members become the error metadata and the decoded ProblemDetails is the cause of the error, it keeps the remote trace id and instance.
+// The response body is read but not closed.
+func FromResponse(res *http.Response, opts ...Option) *app.Error {
+	if res.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+
+	o := newOptions(opts)
+	problem := decodeProblem(res)
+
+	if problem.Status == 0 {
+		problem.Status = res.StatusCode
+	}
+
+	problem.retryAfter = parseRetryAfter(res.Header.Get("Retry-After"))
+
+	title := problem.Title
+
+	if title == "" {
+		title = http.StatusText(problem.Status)
+	}
+
+	errType := o.mapper.Type(problem.Status)
+	appErr := app.NewError(o.code(problem), errType, app.DefaultSeverity(errType), title).
+		SetDetail(problem.Detail).
+		SetRetryAfter(problem.retryAfter).
+		Wrap(problem)
+
+	for field, messages := range problem.Errors {
+		appErr.AddValidationError(app.NewFieldValidationError(field, messages...))
+	}
+
+	for name, value := range problem.Extensions {
+		appErr.SetMetadata(name, value)
+	}
+
+	return appErr
+}
+
+// decodeProblem decodes the problem details of the response, the problem is empty when the body is not json or xml
+func decodeProblem(res *http.Response) ProblemDetails {
+	var problem ProblemDetails
+
+	if res.Body == nil {
+		return problem
+	}
+
+	mediaType, _, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+
+	if err != nil {
+		return problem
+	}
+
+	body := io.LimitReader(res.Body, maxResponseSize)
+
+	switch mediaType {
+	case ContentTypeJSON, "application/json":
+		err = json.NewDecoder(body).Decode(&problem)
+	case ContentTypeXML, "application/xml", "text/xml":
+		err = xml.NewDecoder(body).Decode(&problem)
+	}
+
+	if err != nil {
+		return ProblemDetails{}
+	}
+
+	return problem
+}
+
+// code returns the error code of the problem type
+func (o options) code(problem ProblemDetails) string {
+	if problem.Type == "" || problem.Type == AboutBlank {
+		if code, ok := problem.Extensions[CodeExtension].(string); ok && code != "" {
+			return code
+		}
+
+		if text := http.StatusText(problem.Status); text != "" {
+			return strings.ReplaceAll(strings.ToLower(text), " ", "_")
+		}
+
+		return "http_" + strconv.Itoa(problem.Status)
+	}
+
+	if o.registry != nil {
+		if code, ok := o.registry.Code(problem.Type); ok {
+			return code
+		}
+	}
+
+	return problem.Type
+}
+
+// parseRetryAfter parses the Retry-After header in seconds or as an http date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+
+	return 0
+}
@@ -0,0 +1,187 @@
+package httperror
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Talento90/goliath/app"
+)
+
+func newResponse(status int, contentType string, body string) *http.Response {
+	res := &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+
+	if contentType != "" {
+		res.Header.Set("Content-Type", contentType)
+	}
+
+	return res
+}
+
+func TestFromResponseSuccess(t *testing.T) {
+	require.Nil(t, FromResponse(newResponse(http.StatusOK, "application/json", `{}`)))
+}
+
+func TestFromResponseProblemDetails(t *testing.T) {
+	body := `{
+		"type": "invalid_payment_data",
+		"title": "The payment request is invalid",
+		"detail": "Amount and currency are invalid",
+		"status": 400,
+		"instance": "/payments",
+		"traceId": "remote-trace",
+		"errors": {"amount": ["Amount needs to be positive"]}
+	}`
+
+	appErr := FromResponse(newResponse(http.StatusBadRequest, "application/problem+json; charset=utf-8", body))
+
+	require.NotNil(t, appErr)
+	require.Equal(t, "invalid_payment_data", appErr.Code())
+	require.Equal(t, app.ErrorValidation, appErr.Type())
+	require.Equal(t, app.ErrorSeverityLow, appErr.Severity())
+	require.Equal(t, "The payment request is invalid", appErr.Error())
+	require.Equal(t, "Amount and currency are invalid", appErr.Detail())
+	require.Equal(t, app.FieldValidationErrors{"amount": {"Amount needs to be positive"}}, appErr.ValidationErrors())
+
+	var problem ProblemDetails
+	require.True(t, errors.As(appErr, &problem))
+	require.Equal(t, "remote-trace", problem.TraceID)
+	require.Equal(t, "/payments", problem.Instance)
+}
+
+func TestFromResponseErrorType(t *testing.T) {
+	tt := []struct {
+		name             string
+		status           int
+		expectedType     app.ErrorType
+		expectedSeverity app.ErrorSeverity
+	}{
+		{name: "not found", status: http.StatusNotFound, expectedType: app.ErrorNotFound, expectedSeverity: app.ErrorSeverityLow},
+		{name: "forbidden", status: http.StatusForbidden, expectedType: app.ErrorPermission, expectedSeverity: app.ErrorSeverityLow},
+		{name: "unauthorized", status: http.StatusUnauthorized, expectedType: app.ErrorUnauthorised, expectedSeverity: app.ErrorSeverityLow},
+		{name: "conflict", status: http.StatusConflict, expectedType: app.ErrorConflict, expectedSeverity: app.ErrorSeverityLow},
+		{name: "gateway timeout", status: http.StatusGatewayTimeout, expectedType: app.ErrorTimeout, expectedSeverity: app.ErrorSeverityLow},
+		{name: "service unavailable", status: http.StatusServiceUnavailable, expectedType: app.ErrorUnavailable, expectedSeverity: app.ErrorSeverityMedium},
+		{name: "too many requests", status: http.StatusTooManyRequests, expectedType: app.ErrorRateLimited, expectedSeverity: app.ErrorSeverityLow},
+		{name: "unknown client error", status: http.StatusGone, expectedType: app.ErrorValidation, expectedSeverity: app.ErrorSeverityLow},
+		{name: "unknown server error", status: http.StatusBadGateway, expectedType: app.ErrorInternal, expectedSeverity: app.ErrorSeverityHigh},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			appErr := FromResponse(newResponse(tc.status, "", ""))
+
+			require.Equal(t, tc.expectedType, appErr.Type())
+			require.Equal(t, tc.expectedSeverity, appErr.Severity())
+		})
+	}
+}
+
+func TestFromResponseWithoutProblemDetails(t *testing.T) {
+	appErr := FromResponse(newResponse(http.StatusBadGateway, "text/html", "<html>bad gateway</html>"))
+
+	require.Equal(t, "bad_gateway", appErr.Code())
+	require.Equal(t, http.StatusText(http.StatusBadGateway), appErr.Error())
+	require.Equal(t, app.ErrorInternal, appErr.Type())
+}
+
+func TestFromResponseRetryAfter(t *testing.T) {
+	res := newResponse(http.StatusTooManyRequests, ContentTypeJSON, `{"type":"too_many_requests","title":"Too many requests","status":429}`)
+	res.Header.Set("Retry-After", "3")
+
+	appErr := FromResponse(res)
+
+	require.Equal(t, 3*time.Second, appErr.RetryAfter())
+
+	res = newResponse(http.StatusServiceUnavailable, "", "")
+	res.Header.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+
+	appErr = FromResponse(res)
+
+	require.InDelta(t, time.Hour, appErr.RetryAfter(), float64(2*time.Second))
+}
+
+func TestFromResponseWithRegistry(t *testing.T) {
+	registry := NewRegistry("https://errors.example.com/").
+		Register("payment_not_found", ProblemType{Title: "Payment not found"})
+
+	body := `{"type":"https://errors.example.com/payment_not_found","title":"Payment not found","status":404}`
+	appErr := FromResponse(newResponse(http.StatusNotFound, ContentTypeJSON, body), WithRegistry(registry))
+
+	require.Equal(t, "payment_not_found", appErr.Code())
+
+	appErr = FromResponse(newResponse(http.StatusNotFound, ContentTypeJSON, `{"type":"about:blank","status":404}`), WithRegistry(registry))
+
+	require.Equal(t, "not_found", appErr.Code())
+	require.Equal(t, "Not Found", appErr.Error())
+
+	body = `{"type":"about:blank","title":"Not Found","status":404,"code":"invoice_not_found"}`
+	appErr = FromResponse(newResponse(http.StatusNotFound, ContentTypeJSON, body), WithRegistry(registry))
+
+	require.Equal(t, "invoice_not_found", appErr.Code())
+}
+
+func TestFromResponseWithMapper(t *testing.T) {
+	mapper := NewMapper().RegisterStatus(http.StatusGone, app.ErrorNotFound)
+
+	appErr := FromResponse(newResponse(http.StatusGone, "", ""), WithMapper(mapper))
+
+	require.Equal(t, app.ErrorNotFound, appErr.Type())
+}
+
+func TestFromResponseRoundTrip(t *testing.T) {
+	appCtx := app.FromContext(context.Background())
+	appCtx.SetTraceID("trace-1")
+
+	err := app.NewErrorRateLimited("too_many_payments", "Too many payments").SetRetryAfter(2 * time.Second)
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, Write(rec, New(appCtx, err, "/payments")))
+
+	appErr := FromResponse(rec.Result())
+
+	require.Equal(t, err.Code(), appErr.Code())
+	require.Equal(t, err.Type(), appErr.Type())
+	require.Equal(t, err.Error(), appErr.Error())
+	require.Equal(t, err.RetryAfter(), appErr.RetryAfter())
+
+	var problem ProblemDetails
+	require.True(t, errors.As(appErr, &problem))
+	require.Equal(t, "trace-1", problem.TraceID)
+}
+
+func TestFromResponseXML(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/payments", nil)
+	req.Header.Set("Accept", ContentTypeXML)
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, Render(rec, req, newValidationProblem()))
+
+	appErr := FromResponse(rec.Result())
+
+	require.Equal(t, "invalid_payment_data", appErr.Code())
+	require.Equal(t, app.ErrorValidation, appErr.Type())
+	require.Equal(t, app.FieldValidationErrors{
+		"amount":   {"Amount needs to be positive", "Amount is too big"},
+		"currency": {"currency is required"},
+	}, appErr.ValidationErrors())
+}
+
+func TestFromResponseExtensions(t *testing.T) {
+	body := `{"type":"out_of_credit","title":"You do not have enough credit.","status":403,"balance":30}`
+
+	appErr := FromResponse(newResponse(http.StatusForbidden, ContentTypeJSON, body))
+
+	require.Equal(t, map[string]any{"balance": float64(30)}, appErr.Metadata())
+}
@@ -0,0 +1,133 @@
+package httperror
+
+import (
+	"encoding/xml"
+	"unicode"
+
+	"github.com/Talento90/goliath/app"
+)
+
+// Namespace of the problem details XML document - https://datatracker.ietf.org/doc/html/rfc7807#appendix-A
+const Namespace = "urn:ietf:rfc:7807"
+
+// xmlProblem is the XML representation of the problem details
+type xmlProblem struct {
+	XMLName    xml.Name       `xml:"urn:ietf:rfc:7807 problem"`
+	Type       string         `xml:"type,omitempty"`
+	Title      string         `xml:"title,omitempty"`
+	Detail     string         `xml:"detail,omitempty"`
+	Status     int            `xml:"status,omitempty"`
+	Instance   string         `xml:"instance,omitempty"`
+	TraceID    string         `xml:"traceId,omitempty"`
+	Errors     *xmlErrors     `xml:"errors,omitempty"`
+	Extensions []xmlExtension `xml:",any"`
+}
+
+// xmlErrors lists the validation errors, field names are attributes since they may not be valid element names
+type xmlErrors struct {
+	Fields []xmlField `xml:"field"`
+}
+
+type xmlField struct {
+	Name     string   `xml:"name,attr"`
+	Messages []string `xml:"i"`
+}
+
+// xmlExtension is an extension member, values that are not strings are encoded as json
+type xmlExtension struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+// MarshalXML encodes the extension as an element inheriting the problem namespace
+func (x xmlExtension) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	return e.EncodeElement(x.Value, xml.StartElement{Name: xml.Name{Local: x.XMLName.Local}})
+}
+
+// MarshalXML encodes the problem details as application/problem+xml
+func (pd ProblemDetails) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	p := xmlProblem{
+		Type:     pd.Type,
+		Title:    pd.Title,
+		Detail:   pd.Detail,
+		Status:   pd.Status,
+		Instance: pd.Instance,
+		TraceID:  pd.TraceID,
+	}
+
+	if len(pd.Errors) > 0 {
+		p.Errors = &xmlErrors{}
+
+		for _, field := range sortedKeys(pd.Errors) {
+			p.Errors.Fields = append(p.Errors.Fields, xmlField{Name: field, Messages: pd.Errors[field]})
+		}
+	}
+
+	for _, name := range sortedKeys(pd.Extensions) {
+		if isMember(name) || !isXMLName(name) {
+			continue
+		}
+
+		value, err := extensionText(pd.Extensions[name])
+
+		if err != nil {
+			return err
+		}
+
+		p.Extensions = append(p.Extensions, xmlExtension{XMLName: xml.Name{Local: name}, Value: value})
+	}
+
+	return e.Encode(p)
+}
+
+// UnmarshalXML decodes an application/problem+xml document
+func (pd *ProblemDetails) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var p xmlProblem
+
+	if err := d.DecodeElement(&p, &start); err != nil {
+		return err
+	}
+
+	pd.Type = p.Type
+	pd.Title = p.Title
+	pd.Detail = p.Detail
+	pd.Status = p.Status
+	pd.Instance = p.Instance
+	pd.TraceID = p.TraceID
+
+	if p.Errors != nil && len(p.Errors.Fields) > 0 {
+		pd.Errors = app.FieldValidationErrors{}
+
+		for _, field := range p.Errors.Fields {
+			pd.Errors[field.Name] = append(pd.Errors[field.Name], field.Messages...)
+		}
+	}
+
+	for _, extension := range p.Extensions {
+		if pd.Extensions == nil {
+			pd.Extensions = make(map[string]any)
+		}
+
+		pd.Extensions[extension.XMLName.Local] = extension.Value
+	}
+
+	return nil
+}
+
+// isXMLName reports whether the extension name is a valid element name
+func isXMLName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, r := range name {
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+		case i > 0 && (r == '-' || r == '.' || unicode.IsDigit(r)):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
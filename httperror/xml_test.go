@@ -0,0 +1,46 @@
+package httperror
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Talento90/goliath/app"
+)
+
+func TestProblemDetailsXMLRoundTrip(t *testing.T) {
+	problem := ProblemDetails{
+		Type:     "https://errors.example.com/invalid_payment_data",
+		Title:    "The payment request is invalid",
+		Detail:   "Amount needs to be positive",
+		Status:   400,
+		Instance: "/payments",
+		TraceID:  "trace-1",
+		Errors:   app.FieldValidationErrors{"items[0].amount": {"Amount needs to be positive"}},
+	}
+
+	body, err := xml.Marshal(problem)
+	require.NoError(t, err)
+
+	var decoded ProblemDetails
+	require.NoError(t, xml.Unmarshal(body, &decoded))
+	require.Equal(t, problem, decoded)
+}
+
+func TestProblemDetailsXMLWithoutErrors(t *testing.T) {
+	body, err := xml.Marshal(ProblemDetails{Type: "about:blank", Status: 404})
+	require.NoError(t, err)
+
+	require.Equal(t, `<problem xmlns="urn:ietf:rfc:7807"><type>about:blank</type><status>404</status></problem>`, string(body))
+}
+
+func TestProblemDetailsXMLExtensions(t *testing.T) {
+	body := `<problem xmlns="urn:ietf:rfc:7807"><type>out_of_credit</type><balance>30</balance></problem>`
+
+	var problem ProblemDetails
+	require.NoError(t, xml.Unmarshal([]byte(body), &problem))
+
+	require.Equal(t, "out_of_credit", problem.Type)
+	require.Equal(t, map[string]any{"balance": "30"}, problem.Extensions)
+}
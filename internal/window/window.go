@@ -0,0 +1,64 @@
+// Package window implements a rolling window of counters split into buckets
+package window
+
+import "time"
+
+// bucket holds the counters of a slice of the window
+type bucket[T any] struct {
+	epoch    int64
+	counters T
+}
+
+// Window keeps rolling counters of type T split into buckets, it is not safe for concurrent use
+type Window[T any] struct {
+	size    time.Duration
+	buckets []bucket[T]
+}
+
+// New returns a window of the given size split into buckets
+func New[T any](size time.Duration, buckets int) *Window[T] {
+	if buckets <= 0 {
+		buckets = 1
+	}
+
+	if size < time.Duration(buckets) {
+		size = time.Duration(buckets)
+	}
+
+	return &Window[T]{size: size, buckets: make([]bucket[T], buckets)}
+}
+
+// epoch returns the index of the bucket since the unix epoch
+func (w *Window[T]) epoch(now time.Time) int64 {
+	return now.UnixNano() / int64(w.size/time.Duration(len(w.buckets)))
+}
+
+// Current returns the counters of the bucket at now, resetting it when it belongs to an older epoch
+func (w *Window[T]) Current(now time.Time) *T {
+	epoch := w.epoch(now)
+	b := &w.buckets[epoch%int64(len(w.buckets))]
+
+	if b.epoch != epoch {
+		*b = bucket[T]{epoch: epoch}
+	}
+
+	return &b.counters
+}
+
+// Each calls fn with the counters of every bucket within the window at now
+func (w *Window[T]) Each(now time.Time, fn func(counters T)) {
+	epoch := w.epoch(now)
+
+	for _, b := range w.buckets {
+		if b.epoch > epoch-int64(len(w.buckets)) && b.epoch <= epoch {
+			fn(b.counters)
+		}
+	}
+}
+
+// Reset clears every bucket
+func (w *Window[T]) Reset() {
+	for i := range w.buckets {
+		w.buckets[i] = bucket[T]{}
+	}
+}
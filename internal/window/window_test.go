@@ -0,0 +1,51 @@
+package window
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sum(w *Window[int], now time.Time) int {
+	total := 0
+
+	w.Each(now, func(counters int) {
+		total += counters
+	})
+
+	return total
+}
+
+func TestWindowRolls(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	w := New[int](10*time.Second, 10)
+
+	*w.Current(now) += 2
+	*w.Current(now.Add(5 * time.Second)) += 3
+
+	require.Equal(t, 5, sum(w, now.Add(5*time.Second)))
+	require.Equal(t, 3, sum(w, now.Add(10*time.Second)))
+	require.Equal(t, 0, sum(w, now.Add(15*time.Second)))
+}
+
+func TestWindowResetsStaleBucket(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	w := New[int](10*time.Second, 10)
+
+	*w.Current(now) += 2
+	// same bucket index one window later
+	*w.Current(now.Add(10 * time.Second))++
+
+	require.Equal(t, 1, sum(w, now.Add(10*time.Second)))
+}
+
+func TestWindowReset(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	w := New[int](time.Second, 0)
+
+	*w.Current(now)++
+	w.Reset()
+
+	require.Equal(t, 0, sum(w, now))
+}
@@ -0,0 +1,51 @@
+package ratelimit
+
+import "time"
+
+// leakyBucket lets requests out at a constant pace, queuing up to Burst requests
+type leakyBucket struct {
+	emission time.Duration
+	capacity time.Duration
+	next     time.Time
+}
+
+// NewLeakyBucket returns a limiter that spaces requests evenly at config.Limit requests per config.Interval
+// without bursts, queuing up to config.Burst requests
+func NewLeakyBucket(config Config) Limiter {
+	emission := config.Interval / time.Duration(max(config.Limit, 1))
+
+	return newLimiter(config, &leakyBucket{
+		emission: emission,
+		capacity: emission * time.Duration(max(config.Burst, 0)),
+	})
+}
+
+func (b *leakyBucket) reserve(now time.Time, maxWait time.Duration) Reservation {
+	slot := now
+
+	if b.next.After(now) {
+		slot = b.next
+	}
+
+	delay := slot.Sub(now)
+
+	if delay > b.capacity {
+		return Reservation{ok: false, delay: delay - b.capacity}
+	}
+
+	if delay > maxWait {
+		return Reservation{ok: false, delay: delay}
+	}
+
+	b.next = slot.Add(b.emission)
+
+	return Reservation{ok: true, delay: delay, slot: slot}
+}
+
+// release frees the slot when it is the last one queued, earlier slots are kept to preserve the spacing
+// of the requests queued after them
+func (b *leakyBucket) release(_ time.Time, r Reservation) {
+	if b.next.Equal(r.slot.Add(b.emission)) {
+		b.next = r.slot
+	}
+}
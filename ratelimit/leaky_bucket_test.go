@@ -0,0 +1,33 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeakyBucketSpacesRequests(t *testing.T) {
+	config, clk, _ := newTestConfig(2, time.Second, 5)
+	limiter := NewLeakyBucket(config)
+
+	require.True(t, limiter.Allow())
+	require.False(t, limiter.Allow())
+
+	clk.Advance(500 * time.Millisecond)
+	require.True(t, limiter.Allow())
+	require.False(t, limiter.Allow())
+}
+
+func TestLeakyBucketQueue(t *testing.T) {
+	config, _, _ := newTestConfig(10, time.Second, 2)
+	limiter := NewLeakyBucket(config)
+
+	require.Equal(t, time.Duration(0), limiter.Reserve().Delay())
+	require.Equal(t, 100*time.Millisecond, limiter.Reserve().Delay())
+	require.Equal(t, 200*time.Millisecond, limiter.Reserve().Delay())
+
+	r := limiter.Reserve()
+	require.False(t, r.OK())
+	require.Equal(t, 100*time.Millisecond, r.Delay())
+}
@@ -0,0 +1,158 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Talento90/goliath/app"
+	"github.com/Talento90/goliath/clock"
+	"github.com/Talento90/goliath/sleep"
+)
+
+// ErrorCodeExceeded is the error code returned when the rate limit is exceeded
+const ErrorCodeExceeded = "rate_limit_exceeded"
+
+// infinite is used when there is no maximum wait time
+const infinite = time.Duration(math.MaxInt64)
+
+// Limiter controls how frequently requests are allowed
+type Limiter interface {
+	// Allow reports whether a request may happen now
+	Allow() bool
+	// Reserve books the next available slot and returns how long the caller must wait to use it
+	Reserve() Reservation
+	// Wait blocks until a request may happen or the context is done, giving back the reserved slot when it is done.
+	// It returns an app.Error with the code ErrorCodeExceeded when the wait would exceed the context deadline.
+	Wait(ctx context.Context) error
+}
+
+// Reservation of a request slot
+type Reservation struct {
+	ok    bool
+	delay time.Duration
+	// slot is the time the reserved request happens
+	slot time.Time
+}
+
+// OK reports whether the slot was reserved
+func (r Reservation) OK() bool {
+	return r.ok
+}
+
+// Delay returns how long the caller must wait before using the slot.
+// When the slot was not reserved it is the time the caller should wait before trying again.
+func (r Reservation) Delay() time.Duration {
+	return r.delay
+}
+
+// Config rate limiter
+type Config struct {
+	// Limit is the number of requests allowed per Interval
+	Limit int
+	// Interval of time for the Limit
+	Interval time.Duration
+	// Burst is the maximum number of requests at once for the token bucket
+	// or the maximum number of queued requests for the leaky bucket
+	Burst int
+	// Clock measures the time, a UTC clock is used when nil
+	Clock clock.Clock
+	// Sleeper pauses the go routine on Wait, sleep.New is used when nil
+	Sleeper sleep.Sleeper
+}
+
+// NewConfig returns a config that allows limit requests per interval
+func NewConfig(limit int, interval time.Duration) Config {
+	return Config{
+		Limit:    limit,
+		Interval: interval,
+		Burst:    limit,
+		Clock:    clock.NewUtcClock(),
+		Sleeper:  sleep.New(),
+	}
+}
+
+// NewError creates the error returned when the rate limit is exceeded
+func NewError(retryAfter time.Duration) *app.Error {
+	return app.NewErrorRateLimited(ErrorCodeExceeded, "rate limit exceeded").SetRetryAfter(retryAfter)
+}
+
+// reserver is implemented by every limiter algorithm
+type reserver interface {
+	// reserve books a slot when the delay does not exceed maxWait
+	reserve(now time.Time, maxWait time.Duration) Reservation
+	// release gives back a reserved slot that will not be used
+	release(now time.Time, r Reservation)
+}
+
+// limiter implements the Limiter methods on top of an algorithm
+type limiter struct {
+	mu       sync.Mutex
+	config   Config
+	reserver reserver
+}
+
+func newLimiter(config Config, r reserver) *limiter {
+	if config.Clock == nil {
+		config.Clock = clock.NewUtcClock()
+	}
+
+	if config.Sleeper == nil {
+		config.Sleeper = sleep.New()
+	}
+
+	return &limiter{config: config, reserver: r}
+}
+
+func (l *limiter) reserve(maxWait time.Duration) Reservation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.reserver.reserve(l.config.Clock.Now(), maxWait)
+}
+
+func (l *limiter) release(r Reservation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.reserver.release(l.config.Clock.Now(), r)
+}
+
+func (l *limiter) Allow() bool {
+	return l.reserve(0).OK()
+}
+
+func (l *limiter) Reserve() Reservation {
+	return l.reserve(infinite)
+}
+
+func (l *limiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	maxWait := infinite
+
+	if deadline, ok := ctx.Deadline(); ok {
+		// the context deadline is wall clock time so it is not compared with the limiter clock
+		maxWait = time.Until(deadline)
+	}
+
+	r := l.reserve(maxWait)
+
+	if !r.OK() {
+		return NewError(r.Delay())
+	}
+
+	if r.Delay() == 0 {
+		return nil
+	}
+
+	if err := sleep.WithContext(ctx, l.config.Sleeper, r.Delay()); err != nil {
+		l.release(r)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Talento90/goliath/app"
+	"github.com/Talento90/goliath/clock"
+	"github.com/Talento90/goliath/sleep"
+)
+
+func newTestConfig(limit int, interval time.Duration, burst int) (Config, *clock.Fake, *sleep.Recorder) {
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	sleeper := sleep.NewFake(clk)
+
+	return Config{
+		Limit:    limit,
+		Interval: interval,
+		Burst:    burst,
+		Clock:    clk,
+		Sleeper:  sleeper,
+	}, clk, sleeper
+}
+
+func TestReservationNotOK(t *testing.T) {
+	config, _, _ := newTestConfig(1, time.Second, 0)
+	limiter := NewLeakyBucket(config)
+
+	require.True(t, limiter.Reserve().OK())
+
+	r := limiter.Reserve()
+	require.False(t, r.OK())
+	require.Equal(t, time.Second, r.Delay())
+}
+
+func TestWaitSleepsUntilSlotIsAvailable(t *testing.T) {
+	config, _, sleeper := newTestConfig(2, time.Second, 1)
+	limiter := NewTokenBucket(config)
+
+	require.NoError(t, limiter.Wait(context.Background()))
+	require.NoError(t, limiter.Wait(context.Background()))
+	require.NoError(t, limiter.Wait(context.Background()))
+
+	require.Equal(t, []time.Duration{500 * time.Millisecond, 500 * time.Millisecond}, sleeper.Calls())
+}
+
+func TestWaitExceedsDeadline(t *testing.T) {
+	config, _, _ := newTestConfig(1, time.Hour, 1)
+	limiter := NewTokenBucket(config)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	require.NoError(t, limiter.Wait(ctx))
+
+	err := limiter.Wait(ctx)
+
+	var appErr *app.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, ErrorCodeExceeded, appErr.Code())
+	require.Equal(t, app.ErrorRateLimited, appErr.Type())
+	require.Equal(t, time.Hour, appErr.RetryAfter())
+}
+
+func TestWaitCancelledContext(t *testing.T) {
+	config, _, _ := newTestConfig(1, time.Second, 1)
+	limiter := NewTokenBucket(config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.ErrorIs(t, limiter.Wait(ctx), context.Canceled)
+}
+
+func TestWaitWithinDeadline(t *testing.T) {
+	config, _, sleeper := newTestConfig(1, time.Hour, 1)
+	limiter := NewTokenBucket(config)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Hour)
+	defer cancel()
+
+	require.NoError(t, limiter.Wait(ctx))
+	require.NoError(t, limiter.Wait(ctx))
+	require.Equal(t, []time.Duration{time.Hour}, sleeper.Calls())
+}
+
+// cancelSleeper cancels the context instead of sleeping
+type cancelSleeper struct {
+	cancel context.CancelFunc
+}
+
+func (s cancelSleeper) Sleep(time.Duration) {}
+
+func (s cancelSleeper) SleepContext(ctx context.Context, _ time.Duration) error {
+	s.cancel()
+	return ctx.Err()
+}
+
+func TestWaitCancelledReleasesReservation(t *testing.T) {
+	tt := []struct {
+		name       string
+		newLimiter func(config Config) Limiter
+	}{
+		{name: "token bucket", newLimiter: NewTokenBucket},
+		{name: "leaky bucket", newLimiter: NewLeakyBucket},
+		{name: "sliding window log", newLimiter: NewSlidingWindowLog},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			config, _, _ := newTestConfig(1, time.Second, 1)
+			ctx, cancel := context.WithCancel(context.Background())
+			config.Sleeper = cancelSleeper{cancel: cancel}
+			limiter := tc.newLimiter(config)
+
+			require.True(t, limiter.Allow())
+			require.ErrorIs(t, limiter.Wait(ctx), context.Canceled)
+
+			// the cancelled request does not delay the next one
+			r := limiter.Reserve()
+			require.True(t, r.OK())
+			require.Equal(t, time.Second, r.Delay())
+		})
+	}
+}
+
+func TestNewConfig(t *testing.T) {
+	config := NewConfig(10, time.Second)
+
+	require.Equal(t, 10, config.Limit)
+	require.Equal(t, 10, config.Burst)
+	require.True(t, NewSlidingWindowLog(config).Allow())
+}
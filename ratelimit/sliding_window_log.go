@@ -0,0 +1,53 @@
+package ratelimit
+
+import "time"
+
+// slidingWindowLog keeps the time of every request within the window
+type slidingWindowLog struct {
+	limit  int
+	window time.Duration
+	log    []time.Time
+}
+
+// NewSlidingWindowLog returns a limiter that allows at most config.Limit requests within any config.Interval
+func NewSlidingWindowLog(config Config) Limiter {
+	return newLimiter(config, &slidingWindowLog{
+		limit:  max(config.Limit, 1),
+		window: config.Interval,
+	})
+}
+
+func (w *slidingWindowLog) reserve(now time.Time, maxWait time.Duration) Reservation {
+	expired := 0
+
+	for expired < len(w.log) && !w.log[expired].After(now.Add(-w.window)) {
+		expired++
+	}
+
+	w.log = w.log[expired:]
+
+	slot := now
+
+	if len(w.log) >= w.limit {
+		slot = w.log[len(w.log)-w.limit].Add(w.window)
+	}
+
+	delay := slot.Sub(now)
+
+	if delay > maxWait {
+		return Reservation{ok: false, delay: delay}
+	}
+
+	w.log = append(w.log, slot)
+
+	return Reservation{ok: true, delay: delay, slot: slot}
+}
+
+func (w *slidingWindowLog) release(_ time.Time, r Reservation) {
+	for i := len(w.log) - 1; i >= 0; i-- {
+		if w.log[i].Equal(r.slot) {
+			w.log = append(w.log[:i], w.log[i+1:]...)
+			return
+		}
+	}
+}
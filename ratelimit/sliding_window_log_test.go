@@ -0,0 +1,37 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlidingWindowLogAllow(t *testing.T) {
+	config, clk, _ := newTestConfig(2, time.Second, 0)
+	limiter := NewSlidingWindowLog(config)
+
+	require.True(t, limiter.Allow())
+
+	clk.Advance(600 * time.Millisecond)
+	require.True(t, limiter.Allow())
+	require.False(t, limiter.Allow())
+
+	clk.Advance(400 * time.Millisecond)
+	require.True(t, limiter.Allow())
+	require.False(t, limiter.Allow())
+
+	clk.Advance(600 * time.Millisecond)
+	require.True(t, limiter.Allow())
+}
+
+func TestSlidingWindowLogReserve(t *testing.T) {
+	config, _, _ := newTestConfig(2, time.Second, 0)
+	limiter := NewSlidingWindowLog(config)
+
+	require.Equal(t, time.Duration(0), limiter.Reserve().Delay())
+	require.Equal(t, time.Duration(0), limiter.Reserve().Delay())
+	require.Equal(t, time.Second, limiter.Reserve().Delay())
+	require.Equal(t, time.Second, limiter.Reserve().Delay())
+	require.Equal(t, 2*time.Second, limiter.Reserve().Delay())
+}
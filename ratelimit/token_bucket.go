@@ -0,0 +1,52 @@
+package ratelimit
+
+import "time"
+
+// tokenBucket refills Limit tokens per Interval up to Burst tokens, every request takes one token
+type tokenBucket struct {
+	// emission is the time to refill one token
+	emission time.Duration
+	burst    float64
+	tokens   float64
+	last     time.Time
+}
+
+// NewTokenBucket returns a limiter that allows bursts of up to config.Burst requests
+// and refills config.Limit requests per config.Interval
+func NewTokenBucket(config Config) Limiter {
+	burst := float64(max(config.Burst, 1))
+
+	return newLimiter(config, &tokenBucket{
+		emission: config.Interval / time.Duration(max(config.Limit, 1)),
+		burst:    burst,
+		tokens:   burst,
+	})
+}
+
+func (b *tokenBucket) reserve(now time.Time, maxWait time.Duration) Reservation {
+	if !b.last.IsZero() && now.After(b.last) {
+		b.tokens = min(b.burst, b.tokens+float64(now.Sub(b.last))/float64(b.emission))
+	}
+
+	if now.After(b.last) {
+		b.last = now
+	}
+
+	var delay time.Duration
+
+	if b.tokens < 1 {
+		delay = time.Duration((1 - b.tokens) * float64(b.emission))
+	}
+
+	if delay > maxWait {
+		return Reservation{ok: false, delay: delay}
+	}
+
+	b.tokens--
+
+	return Reservation{ok: true, delay: delay, slot: now.Add(delay)}
+}
+
+func (b *tokenBucket) release(_ time.Time, _ Reservation) {
+	b.tokens = min(b.burst, b.tokens+1)
+}
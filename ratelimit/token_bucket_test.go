@@ -0,0 +1,37 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucketAllowsBurst(t *testing.T) {
+	config, clk, _ := newTestConfig(1, time.Second, 3)
+	limiter := NewTokenBucket(config)
+
+	require.True(t, limiter.Allow())
+	require.True(t, limiter.Allow())
+	require.True(t, limiter.Allow())
+	require.False(t, limiter.Allow())
+
+	clk.Advance(time.Second)
+	require.True(t, limiter.Allow())
+	require.False(t, limiter.Allow())
+
+	clk.Advance(time.Hour)
+	require.True(t, limiter.Allow())
+	require.True(t, limiter.Allow())
+	require.True(t, limiter.Allow())
+	require.False(t, limiter.Allow())
+}
+
+func TestTokenBucketReserve(t *testing.T) {
+	config, _, _ := newTestConfig(10, time.Second, 1)
+	limiter := NewTokenBucket(config)
+
+	require.Equal(t, time.Duration(0), limiter.Reserve().Delay())
+	require.Equal(t, 100*time.Millisecond, limiter.Reserve().Delay())
+	require.Equal(t, 200*time.Millisecond, limiter.Reserve().Delay())
+}
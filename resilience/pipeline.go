@@ -0,0 +1,94 @@
+package resilience
+
+import (
+	"context"
+	"time"
+
+	"github.com/Talento90/goliath/clock"
+)
+
+// Task executed by the pipeline
+type Task[T any] func(ctx context.Context) (T, error)
+
+// Policy applies a resilience strategy around the next policy of the pipeline
+type Policy[T any] interface {
+	// Name identifies the policy in the hooks
+	Name() string
+	// Execute runs the next policy applying the strategy
+	Execute(ctx context.Context, next Task[T]) (T, error)
+}
+
+// Config pipeline
+type Config struct {
+	// OnExecute is called before each policy executes
+	OnExecute func(ctx context.Context, policy string)
+	// OnResult is called after each policy executed with its error and elapsed time
+	OnResult func(ctx context.Context, policy string, err error, elapsed time.Duration)
+	// Clock measures the elapsed time of the policies, a UTC clock is used when nil
+	Clock clock.Clock
+}
+
+// Pipeline composes policies, the first policy is the outermost one.
+// For example New(config, Fallback, Retry, Timeout) falls back when every retry timed out
+// while New(config, Timeout, Retry) bounds the total time of every retry.
+type Pipeline[T any] struct {
+	config   Config
+	policies []Policy[T]
+}
+
+// New returns a pipeline executing the policies in order
+func New[T any](config Config, policies ...Policy[T]) *Pipeline[T] {
+	if config.Clock == nil {
+		config.Clock = clock.NewUtcClock()
+	}
+
+	return &Pipeline[T]{config: config, policies: policies}
+}
+
+// Execute runs the task through every policy of the pipeline
+func (p *Pipeline[T]) Execute(ctx context.Context, task Task[T]) (T, error) {
+	next := task
+
+	for i := len(p.policies) - 1; i >= 0; i-- {
+		next = p.observe(p.policies[i], next)
+	}
+
+	return next(ctx)
+}
+
+// observe calls the hooks around the policy
+func (p *Pipeline[T]) observe(policy Policy[T], next Task[T]) Task[T] {
+	return func(ctx context.Context) (T, error) {
+		if p.config.OnExecute != nil {
+			p.config.OnExecute(ctx, policy.Name())
+		}
+
+		start := p.config.Clock.Now()
+		result, err := policy.Execute(ctx, next)
+
+		if p.config.OnResult != nil {
+			p.config.OnResult(ctx, policy.Name(), err, p.config.Clock.Since(start))
+		}
+
+		return result, err
+	}
+}
+
+// policy implements Policy with a function
+type policy[T any] struct {
+	name string
+	fn   func(ctx context.Context, next Task[T]) (T, error)
+}
+
+// NewPolicy returns a custom policy
+func NewPolicy[T any](name string, fn func(ctx context.Context, next Task[T]) (T, error)) Policy[T] {
+	return policy[T]{name: name, fn: fn}
+}
+
+func (p policy[T]) Name() string {
+	return p.name
+}
+
+func (p policy[T]) Execute(ctx context.Context, next Task[T]) (T, error) {
+	return p.fn(ctx, next)
+}
@@ -0,0 +1,71 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// record returns a policy that appends its name before and after the next policy
+func record(name string, calls *[]string) Policy[string] {
+	return NewPolicy(name, func(ctx context.Context, next Task[string]) (string, error) {
+		*calls = append(*calls, "before "+name)
+		result, err := next(ctx)
+		*calls = append(*calls, "after "+name)
+
+		return result, err
+	})
+}
+
+func TestPipelineExecutesPoliciesInOrder(t *testing.T) {
+	var calls []string
+	pipeline := New(Config{}, record("outer", &calls), record("inner", &calls))
+
+	result, err := pipeline.Execute(context.Background(), func(context.Context) (string, error) {
+		calls = append(calls, "task")
+		return "My result", nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "My result", result)
+	require.Equal(t, []string{"before outer", "before inner", "task", "after inner", "after outer"}, calls)
+}
+
+func TestPipelineHooks(t *testing.T) {
+	expectedErr := errors.New("connection refused")
+	var executed []string
+	var results []error
+	var calls []string
+
+	config := Config{
+		OnExecute: func(_ context.Context, policy string) {
+			executed = append(executed, policy)
+		},
+		OnResult: func(_ context.Context, policy string, err error, elapsed time.Duration) {
+			results = append(results, err)
+			require.GreaterOrEqual(t, elapsed, time.Duration(0))
+		},
+	}
+
+	pipeline := New(config, record("outer", &calls), record("inner", &calls))
+
+	_, err := pipeline.Execute(context.Background(), func(context.Context) (string, error) {
+		return "", expectedErr
+	})
+
+	require.ErrorIs(t, err, expectedErr)
+	require.Equal(t, []string{"outer", "inner"}, executed)
+	require.Equal(t, []error{expectedErr, expectedErr}, results)
+}
+
+func TestPipelineWithoutPolicies(t *testing.T) {
+	result, err := New[int](Config{}).Execute(context.Background(), func(context.Context) (int, error) {
+		return 10, nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, 10, result)
+}
@@ -0,0 +1,83 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Talento90/goliath/app"
+	"github.com/Talento90/goliath/breaker"
+	"github.com/Talento90/goliath/bulkhead"
+	"github.com/Talento90/goliath/fallback"
+	"github.com/Talento90/goliath/retry"
+)
+
+// ErrorCodeTimeout is the error code returned when the timeout policy is exceeded
+const ErrorCodeTimeout = "timeout_exceeded"
+
+// Retry retries the next policy using retry.ExecuteContext
+func Retry[T any](config retry.Config) Policy[T] {
+	return NewPolicy("retry", func(ctx context.Context, next Task[T]) (T, error) {
+		return retry.ExecuteContext(ctx, config, next)
+	})
+}
+
+// Timeout cancels the context of the next policy when the timeout is exceeded,
+// returning an app.Error of type app.ErrorTimeout
+func Timeout[T any](timeout time.Duration) Policy[T] {
+	return NewPolicy("timeout", func(ctx context.Context, next Task[T]) (T, error) {
+		timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		result, err := next(timeoutCtx)
+
+		if err != nil && ctx.Err() == nil && errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
+			return result, app.NewErrorTimeout(ErrorCodeTimeout, "timeout exceeded").Wrap(err)
+		}
+
+		return result, err
+	})
+}
+
+// Breaker executes the next policy through the circuit breaker
+func Breaker[T any](b *breaker.Breaker) Policy[T] {
+	return NewPolicy("breaker", func(ctx context.Context, next Task[T]) (T, error) {
+		return breaker.Execute(ctx, b, next)
+	})
+}
+
+// Bulkhead executes the next policy through the bulkhead
+func Bulkhead[T any](b *bulkhead.Bulkhead) Policy[T] {
+	return NewPolicy("bulkhead", func(ctx context.Context, next Task[T]) (T, error) {
+		return bulkhead.Execute(ctx, b, next)
+	})
+}
+
+// Fallback calls the fallback function when the next policy returns an app.Error of any of the given types.
+// Every error falls back when no types are given.
+func Fallback[T any](fn func(ctx context.Context, err error) (T, error), types ...app.ErrorType) Policy[T] {
+	var matchers []fallback.Matcher
+
+	if len(types) > 0 {
+		matchers = append(matchers, fallback.Type(types...))
+	}
+
+	return FallbackOn(fn, matchers...)
+}
+
+// FallbackOn calls the fallback function when the error returned by the next policy matches any of the matchers.
+// Every error falls back when no matchers are given.
+func FallbackOn[T any](fn func(ctx context.Context, err error) (T, error), matchers ...fallback.Matcher) Policy[T] {
+	return NewPolicy("fallback", func(ctx context.Context, next Task[T]) (T, error) {
+		result, err := fallback.Execute(ctx, next, fn, matchers...)
+		return result.Value, err
+	})
+}
+
+// FallbackValue returns the value when the next policy returns an app.Error of any of the given types.
+// Every error falls back when no types are given.
+func FallbackValue[T any](value T, types ...app.ErrorType) Policy[T] {
+	return Fallback(func(context.Context, error) (T, error) {
+		return value, nil
+	}, types...)
+}
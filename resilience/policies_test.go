@@ -0,0 +1,150 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Talento90/goliath/app"
+	"github.com/Talento90/goliath/backoff"
+	"github.com/Talento90/goliath/breaker"
+	"github.com/Talento90/goliath/bulkhead"
+	"github.com/Talento90/goliath/fallback"
+	"github.com/Talento90/goliath/retry"
+	"github.com/Talento90/goliath/sleep"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	counter := 0
+	config := retry.Config{Times: 3, Sleeper: sleep.NewRecorder(), Backoff: backoff.Constant(time.Millisecond)}
+	pipeline := New(Config{}, Retry[string](config))
+
+	result, err := pipeline.Execute(context.Background(), func(context.Context) (string, error) {
+		counter++
+
+		if counter < 3 {
+			return "", errors.New("connection refused")
+		}
+
+		return "My result", nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "My result", result)
+	require.Equal(t, 3, counter)
+}
+
+func TestTimeoutPolicy(t *testing.T) {
+	pipeline := New(Config{}, Timeout[string](time.Millisecond))
+
+	_, err := pipeline.Execute(context.Background(), func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+
+	var appErr *app.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, app.ErrorTimeout, appErr.Type())
+	require.Equal(t, ErrorCodeTimeout, appErr.Code())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestBreakerPolicy(t *testing.T) {
+	b := breaker.New(breaker.Config{ConsecutiveFailures: 1, CoolDown: time.Minute})
+	pipeline := New(Config{}, Breaker[string](b))
+	task := func(context.Context) (string, error) {
+		return "", errors.New("connection refused")
+	}
+
+	_, err := pipeline.Execute(context.Background(), task)
+	require.False(t, breaker.IsOpen(err))
+
+	_, err = pipeline.Execute(context.Background(), task)
+	require.True(t, breaker.IsOpen(err))
+}
+
+func TestBulkheadPolicy(t *testing.T) {
+	b := bulkhead.New(bulkhead.NewConfig(1))
+	pipeline := New(Config{}, Bulkhead[string](b))
+
+	result, err := pipeline.Execute(context.Background(), func(context.Context) (string, error) {
+		require.Equal(t, 1, b.InFlight())
+		return "My result", nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "My result", result)
+}
+
+func TestFallbackValuePolicy(t *testing.T) {
+	pipeline := New(Config{}, FallbackValue("cached", app.ErrorTimeout, app.ErrorInternal))
+
+	result, err := pipeline.Execute(context.Background(), func(context.Context) (string, error) {
+		return "", app.NewErrorTimeout("db_timeout", "Database timeout")
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "cached", result)
+
+	validationErr := app.NewErrorValidation("invalid_user", "Invalid user")
+	_, err = pipeline.Execute(context.Background(), func(context.Context) (string, error) {
+		return "", validationErr
+	})
+
+	require.ErrorIs(t, err, validationErr)
+}
+
+func TestFallbackPolicyAnyError(t *testing.T) {
+	expectedErr := errors.New("connection refused")
+	var fallbackErr error
+
+	pipeline := New(Config{}, Fallback(func(_ context.Context, err error) (string, error) {
+		fallbackErr = err
+		return "alternate", nil
+	}))
+
+	result, err := pipeline.Execute(context.Background(), func(context.Context) (string, error) {
+		return "", expectedErr
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "alternate", result)
+	require.ErrorIs(t, fallbackErr, expectedErr)
+}
+
+func TestFallbackOnPolicy(t *testing.T) {
+	pipeline := New(Config{}, FallbackOn(func(context.Context, error) (string, error) {
+		return "cached", nil
+	}, fallback.Code("db_down"), fallback.Is(context.DeadlineExceeded)))
+
+	result, err := pipeline.Execute(context.Background(), func(context.Context) (string, error) {
+		return "", app.NewErrorInternal("db_down", "Database down")
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "cached", result)
+}
+
+func TestPipelineComposition(t *testing.T) {
+	counter := 0
+	config := retry.Config{Times: 3, Sleeper: sleep.NewRecorder(), Backoff: backoff.Constant(time.Millisecond)}
+	pipeline := New(Config{},
+		FallbackValue("cached", app.ErrorTimeout),
+		Retry[string](config),
+		Timeout[string](time.Millisecond),
+	)
+
+	result, err := pipeline.Execute(context.Background(), func(ctx context.Context) (string, error) {
+		counter++
+		<-ctx.Done()
+
+		return "", ctx.Err()
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "cached", result)
+	require.Equal(t, 3, counter)
+}
@@ -0,0 +1,127 @@
+package retry
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Talento90/goliath/app"
+	"github.com/Talento90/goliath/clock"
+	"github.com/Talento90/goliath/internal/window"
+)
+
+// ErrorCodeBudgetExhausted is the error code returned when the retry budget denies a retry
+const ErrorCodeBudgetExhausted = "retry_budget_exhausted"
+
+// budgetBuckets is the number of buckets the budget window is split into
+const budgetBuckets = 10
+
+// BudgetConfig retry budget
+type BudgetConfig struct {
+	// Ratio of retries earned by each successful call, 0.1 allows one retry every ten successful calls
+	Ratio float64
+	// MinRetries allowed within the window regardless of the successful calls
+	MinRetries int
+	// Window of time the successful calls and retries are accounted for
+	Window time.Duration
+	// Clock measures the window, a UTC clock is used when nil
+	Clock clock.Clock
+}
+
+// NewBudgetConfig returns a config that allows retrying 20% of the successful calls
+// plus 10 retries within 10 seconds
+func NewBudgetConfig() BudgetConfig {
+	return BudgetConfig{
+		Ratio:      0.2,
+		MinRetries: 10,
+		Window:     10 * time.Second,
+		Clock:      clock.NewUtcClock(),
+	}
+}
+
+// BudgetStats are the budget statistics within the window
+type BudgetStats struct {
+	// Successes is the number of successful calls
+	Successes int
+	// Retries is the number of allowed retries
+	Retries int
+	// Denied is the number of denied retries
+	Denied int
+	// Available is the number of retries that can be done
+	Available int
+}
+
+// budgetCounts counts the calls within a bucket of the window
+type budgetCounts struct {
+	successes int
+	retries   int
+	denied    int
+}
+
+// Budget limits the retries across every execution sharing it to prevent retry storms.
+// Each successful call deposits a fraction of a token and each retry withdraws a token.
+type Budget struct {
+	mu     sync.Mutex
+	config BudgetConfig
+	window *window.Window[budgetCounts]
+}
+
+// NewBudget returns a retry budget that can be shared across go routines
+func NewBudget(config BudgetConfig) *Budget {
+	if config.Clock == nil {
+		config.Clock = clock.NewUtcClock()
+	}
+
+	return &Budget{config: config, window: window.New[budgetCounts](config.Window, budgetBuckets)}
+}
+
+// Stats returns the budget statistics within the window
+func (b *Budget) Stats() BudgetStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.stats(b.config.Clock.Now())
+}
+
+// deposit registers a successful call
+func (b *Budget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.window.Current(b.config.Clock.Now()).successes++
+}
+
+// withdraw reports whether a retry is allowed and registers it
+func (b *Budget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.config.Clock.Now()
+
+	if b.stats(now).Available < 1 {
+		b.window.Current(now).denied++
+		return false
+	}
+
+	b.window.Current(now).retries++
+
+	return true
+}
+
+func (b *Budget) stats(now time.Time) BudgetStats {
+	var stats BudgetStats
+
+	b.window.Each(now, func(counts budgetCounts) {
+		stats.Successes += counts.successes
+		stats.Retries += counts.retries
+		stats.Denied += counts.denied
+	})
+
+	stats.Available = max(b.config.MinRetries+int(b.config.Ratio*float64(stats.Successes))-stats.Retries, 0)
+
+	return stats
+}
+
+// budgetExhausted returns the error when the budget denies a retry
+func budgetExhausted(cause error) error {
+	return app.NewErrorUnavailable(ErrorCodeBudgetExhausted, "retry budget exhausted").Wrap(cause)
+}
@@ -0,0 +1,153 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Talento90/goliath/app"
+	"github.com/Talento90/goliath/backoff"
+	"github.com/Talento90/goliath/clock"
+	"github.com/Talento90/goliath/sleep"
+)
+
+func newTestBudget(ratio float64, minRetries int) (*Budget, *clock.Fake) {
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	return NewBudget(BudgetConfig{Ratio: ratio, MinRetries: minRetries, Window: 10 * time.Second, Clock: clk}), clk
+}
+
+func TestBudgetWithdraw(t *testing.T) {
+	budget, _ := newTestBudget(0.5, 1)
+
+	require.True(t, budget.withdraw())
+	require.False(t, budget.withdraw())
+
+	budget.deposit()
+	budget.deposit()
+
+	require.True(t, budget.withdraw())
+	require.False(t, budget.withdraw())
+
+	require.Equal(t, BudgetStats{Successes: 2, Retries: 2, Denied: 2, Available: 0}, budget.Stats())
+}
+
+func TestBudgetWindowExpires(t *testing.T) {
+	budget, clk := newTestBudget(0.5, 1)
+
+	require.True(t, budget.withdraw())
+	require.False(t, budget.withdraw())
+
+	clk.Advance(10 * time.Second)
+
+	require.Equal(t, BudgetStats{Available: 1}, budget.Stats())
+	require.True(t, budget.withdraw())
+}
+
+func TestExecuteDeniedByBudget(t *testing.T) {
+	expectedErr := errors.New("connection refused")
+	budget, _ := newTestBudget(0.1, 2)
+	counter := 0
+
+	task := func() (int, error) {
+		counter++
+		return 0, expectedErr
+	}
+
+	config := Config{
+		Times:   5,
+		Sleeper: sleep.NewRecorder(),
+		Budget:  budget,
+	}
+
+	_, err := Execute(config, task)
+
+	var appErr *app.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, ErrorCodeBudgetExhausted, appErr.Code())
+	require.Equal(t, app.ErrorUnavailable, appErr.Type())
+	require.ErrorIs(t, err, expectedErr)
+	require.Equal(t, 3, counter)
+
+	// the budget is shared so the next execution cannot retry
+	counter = 0
+	_, err = Execute(config, task)
+
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, ErrorCodeBudgetExhausted, appErr.Code())
+	require.Equal(t, 1, counter)
+	require.Equal(t, BudgetStats{Retries: 2, Denied: 2}, budget.Stats())
+}
+
+func TestExecuteAbandonedRetriesDoNotSpendBudget(t *testing.T) {
+	tt := []struct {
+		name   string
+		config func(config Config) Config
+	}{
+		{
+			name: "vetoed by the hook",
+			config: func(config Config) Config {
+				config.OnRetry = func(context.Context, Attempt) bool {
+					return false
+				}
+
+				return config
+			},
+		},
+		{
+			name: "exceeds the max elapsed time",
+			config: func(config Config) Config {
+				config.Backoff = backoff.Constant(time.Minute)
+				config.MaxElapsedTime = time.Second
+
+				return config
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			budget, _ := newTestBudget(0.1, 2)
+
+			config := tc.config(Config{
+				Times:   5,
+				Sleeper: sleep.NewRecorder(),
+				Budget:  budget,
+			})
+
+			_, err := Execute(config, func() (int, error) {
+				return 0, errors.New("connection refused")
+			})
+
+			require.Error(t, err)
+			require.Equal(t, BudgetStats{Available: 2}, budget.Stats())
+		})
+	}
+}
+
+func TestExecuteSuccessDepositsInBudget(t *testing.T) {
+	budget, _ := newTestBudget(0.1, 0)
+
+	config := Config{
+		Times:  3,
+		Budget: budget,
+	}
+
+	for i := 0; i < 10; i++ {
+		_, err := Execute(config, func() (int, error) {
+			return 1, nil
+		})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, BudgetStats{Successes: 10, Available: 1}, budget.Stats())
+}
+
+func TestNewBudgetConfig(t *testing.T) {
+	budget := NewBudget(NewBudgetConfig())
+
+	require.Equal(t, 10, budget.Stats().Available)
+}
@@ -0,0 +1,84 @@
+package retry
+
+import (
+	"errors"
+
+	"github.com/Talento90/goliath/app"
+	"github.com/Talento90/goliath/breaker"
+)
+
+// permanentError marks an error that should not be retried
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent wraps the error to stop retrying the task regardless of the classifier.
+// The wrapped error is returned to the caller without the permanent marker.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether any error in the chain was marked as permanent
+func IsPermanent(err error) bool {
+	var permanentErr *permanentError
+	return errors.As(err, &permanentErr)
+}
+
+// DefaultIsRetryable retries app errors of type timeout, internal, unavailable and rate limited
+// and any error that is not an app.Error, so the Hint carried by unavailable and rate limited errors is honoured.
+// Errors like validation, permission, unauthorised, conflict or not found will never succeed so they are not retried.
+func DefaultIsRetryable(err error) bool {
+	var appErr *app.Error
+
+	if !errors.As(err, &appErr) {
+		return true
+	}
+
+	switch appErr.Type() {
+	case app.ErrorTimeout, app.ErrorInternal, app.ErrorUnavailable, app.ErrorRateLimited:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRetryable checks if the error is not permanent, the circuit is not open, the budget of a nested retry
+// is not exhausted and the error is accepted by the classifier
+func (c Config) isRetryable(err error) bool {
+	if IsPermanent(err) || breaker.IsOpen(err) || isBudgetExhausted(err) {
+		return false
+	}
+
+	if c.IsRetryable != nil {
+		return c.IsRetryable(err)
+	}
+
+	return DefaultIsRetryable(err)
+}
+
+// isBudgetExhausted reports whether the error was returned because a retry budget denied a retry
+func isBudgetExhausted(err error) bool {
+	var appErr *app.Error
+	return errors.As(err, &appErr) && appErr.Code() == ErrorCodeBudgetExhausted
+}
+
+// unwrapPermanent removes the permanent marker from the error
+func unwrapPermanent(err error) error {
+	if permanentErr, ok := err.(*permanentError); ok { //nolint:errorlint // only the top level marker is removed
+		return permanentErr.err
+	}
+
+	return err
+}
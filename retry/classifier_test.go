@@ -0,0 +1,149 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Talento90/goliath/app"
+	"github.com/Talento90/goliath/breaker"
+	"github.com/Talento90/goliath/sleep"
+)
+
+func TestDefaultIsRetryable(t *testing.T) {
+	tt := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{name: "generic error", err: errors.New("connection refused"), retryable: true},
+		{name: "internal error", err: app.NewErrorInternal("db", "db error"), retryable: true},
+		{name: "timeout error", err: app.NewErrorTimeout("db", "db timeout"), retryable: true},
+		{name: "wrapped timeout error", err: fmt.Errorf("fetch: %w", app.NewErrorTimeout("db", "db timeout")), retryable: true},
+		{name: "unavailable error", err: app.NewErrorUnavailable("db", "db unavailable"), retryable: true},
+		{name: "rate limited error", err: app.NewErrorRateLimited("api", "too many requests"), retryable: true},
+		{name: "validation error", err: app.NewErrorValidation("user", "invalid user"), retryable: false},
+		{name: "permission error", err: app.NewErrorPermission("user", "forbidden"), retryable: false},
+		{name: "unauthorised error", err: app.NewErrorUnauthorised("user", "unauthorised"), retryable: false},
+		{name: "conflict error", err: app.NewErrorConflict("user", "already exists"), retryable: false},
+		{name: "not found error", err: app.NewErrorNotFound("user", "not found"), retryable: false},
+		{name: "wrapped not found error", err: fmt.Errorf("fetch: %w", app.NewErrorNotFound("user", "not found")), retryable: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.retryable, DefaultIsRetryable(tc.err))
+		})
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := Permanent(cause)
+
+	require.True(t, IsPermanent(err))
+	require.True(t, IsPermanent(fmt.Errorf("fetch: %w", err)))
+	require.False(t, IsPermanent(cause))
+	require.ErrorIs(t, err, cause)
+	require.Equal(t, cause.Error(), err.Error())
+	require.NoError(t, Permanent(nil))
+}
+
+func TestExecuteStopsOnNonRetryableError(t *testing.T) {
+	expectedErr := app.NewErrorValidation("invalid_user", "Invalid user")
+	counter := 0
+
+	task := func() (int, error) {
+		counter++
+		return 0, expectedErr
+	}
+
+	sleeper := sleep.NewRecorder()
+
+	_, err := Execute(Config{Times: 3, Sleeper: sleeper}, task)
+
+	require.ErrorIs(t, err, expectedErr)
+	require.Equal(t, 1, counter)
+	require.Empty(t, sleeper.Calls())
+}
+
+func TestExecuteStopsOnPermanentError(t *testing.T) {
+	expectedErr := errors.New("Couldn't connect to the database")
+	counter := 0
+
+	task := func() (int, error) {
+		counter++
+		return 0, Permanent(expectedErr)
+	}
+
+	sleeper := sleep.NewRecorder()
+
+	_, err := Execute(Config{Times: 3, Sleeper: sleeper}, task)
+
+	require.Equal(t, expectedErr, err)
+	require.Equal(t, 1, counter)
+	require.Empty(t, sleeper.Calls())
+}
+
+func TestExecuteWithCustomClassifier(t *testing.T) {
+	counter := 0
+
+	task := func() (int, error) {
+		counter++
+		return 0, app.NewErrorNotFound("user_not_found", "User not found")
+	}
+
+	sleeper := sleep.NewRecorder()
+
+	config := Config{
+		Times:   3,
+		Sleeper: sleeper,
+		IsRetryable: func(error) bool {
+			return true
+		},
+	}
+
+	_, err := Execute(config, task)
+
+	require.Error(t, err)
+	require.Equal(t, 3, counter)
+	require.Len(t, sleeper.Calls(), 2)
+}
+
+func TestExecuteStopsWhenCircuitIsOpen(t *testing.T) {
+	counter := 0
+
+	task := func() (int, error) {
+		counter++
+		return 0, errors.New("connection refused")
+	}
+
+	sleeper := sleep.NewRecorder()
+
+	config := Config{
+		Times:   5,
+		Sleeper: sleeper,
+		Breaker: breaker.New(breaker.Config{ConsecutiveFailures: 2, CoolDown: time.Minute}),
+		IsRetryable: func(error) bool {
+			return true
+		},
+	}
+
+	_, err := Execute(config, task)
+
+	require.True(t, breaker.IsOpen(err))
+	require.ErrorContains(t, errors.Unwrap(err), "connection refused")
+	require.Equal(t, 2, counter)
+	require.Len(t, sleeper.Calls(), 1)
+	require.Equal(t, breaker.StateOpen, config.Breaker.State())
+}
+
+func TestIsRetryableStopsOnNestedBudgetExhausted(t *testing.T) {
+	err := budgetExhausted(errors.New("connection refused"))
+
+	require.True(t, DefaultIsRetryable(err))
+	require.False(t, Config{}.isRetryable(fmt.Errorf("fetch: %w", err)))
+}
@@ -0,0 +1,55 @@
+package retry
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrorCodeExhausted is the error code returned when all the attempts failed and the errors are aggregated
+const ErrorCodeExhausted = "retry_exhausted"
+
+// AttemptError describes the error returned by a single attempt
+type AttemptError struct {
+	// Attempt number starting at 1
+	Attempt int
+	// Delay applied after the attempt
+	Delay time.Duration
+	// Time when the attempt failed
+	Time time.Time
+	// Err returned by the task
+	Err error
+}
+
+func (e AttemptError) Error() string {
+	return fmt.Sprintf("attempt %d: %s", e.Attempt, e.Err)
+}
+
+// Unwrap returns the task error
+func (e AttemptError) Unwrap() error {
+	return e.Err
+}
+
+// Errors aggregates the errors of every attempt, it is compatible with errors.Is and errors.As
+type Errors []AttemptError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the errors of every attempt
+func (e Errors) Unwrap() []error {
+	errs := make([]error, len(e))
+
+	for i, err := range e {
+		errs[i] = err
+	}
+
+	return errs
+}
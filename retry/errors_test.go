@@ -0,0 +1,119 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Talento90/goliath/app"
+	"github.com/Talento90/goliath/backoff"
+	"github.com/Talento90/goliath/clock"
+	"github.com/Talento90/goliath/sleep"
+)
+
+func TestExecuteAggregateErrors(t *testing.T) {
+	timeoutErr := app.NewErrorTimeout("db_timeout", "Database timeout")
+	connectionErr := errors.New("connection refused")
+	errs := []error{timeoutErr, connectionErr, connectionErr}
+	counter := 0
+
+	task := func() (int, error) {
+		err := errs[counter]
+		counter++
+
+		return 0, err
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(start)
+	config := Config{
+		Times:           3,
+		Sleeper:         sleep.NewFake(clk),
+		Backoff:         backoff.Constant(time.Second),
+		AggregateErrors: true,
+		Clock:           clk,
+	}
+
+	_, err := Execute(config, task)
+
+	var appErr *app.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, ErrorCodeExhausted, appErr.Code())
+	require.ErrorIs(t, err, timeoutErr)
+	require.ErrorIs(t, err, connectionErr)
+
+	var attemptErrs Errors
+	require.ErrorAs(t, err, &attemptErrs)
+	require.Equal(t, Errors{
+		{Attempt: 1, Delay: time.Second, Time: start, Err: timeoutErr},
+		{Attempt: 2, Delay: time.Second, Time: start.Add(time.Second), Err: connectionErr},
+		{Attempt: 3, Time: start.Add(2 * time.Second), Err: connectionErr},
+	}, attemptErrs)
+
+	var attemptErr AttemptError
+	require.ErrorAs(t, err, &attemptErr)
+	require.Equal(t, 1, attemptErr.Attempt)
+	require.Equal(t, "attempt 1: Database timeout\nattempt 2: connection refused\nattempt 3: connection refused", attemptErrs.Error())
+}
+
+func TestExecuteContextAggregateErrorsWhenCancelled(t *testing.T) {
+	expectedErr := errors.New("connection refused")
+	ctx, cancel := context.WithCancel(context.Background())
+
+	task := func(context.Context) (int, error) {
+		cancel()
+		return 0, expectedErr
+	}
+
+	config := Config{
+		Times:           3,
+		Sleeper:         sleep.NewRecorder(),
+		AggregateErrors: true,
+	}
+
+	_, err := ExecuteContext(ctx, config, task)
+
+	var appErr *app.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, ErrorCodeCancelled, appErr.Code())
+
+	var attemptErrs Errors
+	require.ErrorAs(t, err, &attemptErrs)
+	require.Len(t, attemptErrs, 1)
+	require.ErrorIs(t, err, expectedErr)
+}
+
+func TestExecuteAggregateErrorsWhenNotRetryable(t *testing.T) {
+	connectionErr := errors.New("connection refused")
+	notFoundErr := app.NewErrorNotFound("user_not_found", "User not found")
+	errs := []error{connectionErr, notFoundErr}
+	counter := 0
+
+	task := func() (int, error) {
+		err := errs[counter]
+		counter++
+
+		return 0, err
+	}
+
+	config := Config{
+		Times:           3,
+		Sleeper:         sleep.NewRecorder(),
+		AggregateErrors: true,
+	}
+
+	_, err := Execute(config, task)
+
+	var attemptErrs Errors
+	require.ErrorAs(t, err, &attemptErrs)
+	require.Len(t, attemptErrs, 2)
+	require.ErrorIs(t, err, connectionErr)
+
+	var appErr *app.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, app.ErrorNotFound, appErr.Type())
+	require.Equal(t, 2, counter)
+}
@@ -0,0 +1,174 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Talento90/goliath/app"
+	"github.com/Talento90/goliath/backoff"
+	"github.com/Talento90/goliath/breaker"
+	"github.com/Talento90/goliath/clock"
+	"github.com/Talento90/goliath/sleep"
+)
+
+// execution holds the state of a single retry loop
+type execution struct {
+	config   Config
+	sleeper  sleep.Sleeper
+	strategy backoff.Backoff
+	clock    clock.Clock
+	start    time.Time
+	lastErr  error
+	errs     Errors
+}
+
+func newExecution(config Config) *execution {
+	exec := &execution{
+		config:   config,
+		sleeper:  config.Sleeper,
+		strategy: config.backoff(),
+		clock:    config.Clock,
+	}
+
+	if exec.sleeper == nil {
+		exec.sleeper = sleep.New()
+	}
+
+	if exec.clock == nil {
+		exec.clock = clock.NewUtcClock()
+	}
+
+	exec.start = exec.clock.Now()
+
+	return exec
+}
+
+// elapsed returns the time since the execution started
+func (e *execution) elapsed() time.Duration {
+	return e.clock.Since(e.start)
+}
+
+// exceeds reports whether waiting the delay exceeds the max elapsed time
+func (e *execution) exceeds(delay time.Duration) bool {
+	return e.config.MaxElapsedTime > 0 && e.elapsed()+delay > e.config.MaxElapsedTime
+}
+
+// withElapsedTime returns a context cancelled when the max elapsed time is exceeded, the remaining time
+// is measured with the clock so every attempt is bounded by the time left to the execution
+func (e *execution) withElapsedTime(ctx context.Context) (context.Context, context.CancelFunc) {
+	if e.config.MaxElapsedTime <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, e.config.MaxElapsedTime-e.elapsed())
+}
+
+// expired reports whether the max elapsed time was exceeded by the attempts running with the context
+func (e *execution) expired(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.DeadlineExceeded) || e.exceeds(0)
+}
+
+// fail registers the error returned by the attempt
+func (e *execution) fail(number int, err error) {
+	e.lastErr = unwrapPermanent(err)
+	e.errs = append(e.errs, AttemptError{Attempt: number, Time: e.clock.Now(), Err: e.lastErr})
+}
+
+// delay returns the first positive delay requested by an error in the chain implementing Hint, otherwise the backoff delay
+func (e *execution) delay(number int, err error) time.Duration {
+	if retryAfter := retryAfter(err); retryAfter > 0 {
+		if e.config.MaxRetryAfter > 0 {
+			return min(retryAfter, e.config.MaxRetryAfter)
+		}
+
+		return retryAfter
+	}
+
+	return e.strategy.Delay(number)
+}
+
+// retryAfter walks the error chain until it finds a Hint with a positive delay
+func retryAfter(err error) time.Duration {
+	if err == nil {
+		return 0
+	}
+
+	if hint, ok := err.(Hint); ok && hint.RetryAfter() > 0 { //nolint:errorlint // the chain is walked below
+		return hint.RetryAfter()
+	}
+
+	switch wrapped := err.(type) { //nolint:errorlint // the chain is walked manually to skip hints without a delay
+	case interface{ Unwrap() error }:
+		return retryAfter(wrapped.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, err := range wrapped.Unwrap() {
+			if delay := retryAfter(err); delay > 0 {
+				return delay
+			}
+		}
+	}
+
+	return 0
+}
+
+// retry registers the delay of the failed attempt and reports whether the hook allows retrying
+func (e *execution) retry(ctx context.Context, number int, delay time.Duration) bool {
+	if n := len(e.errs); n > 0 && e.errs[n-1].Attempt == number {
+		e.errs[n-1].Delay = delay
+	}
+
+	if e.config.OnRetry == nil {
+		return true
+	}
+
+	return e.config.OnRetry(ctx, Attempt{Number: number, Err: e.lastErr, Delay: delay, Elapsed: e.elapsed()})
+}
+
+// circuitOpen reports whether the circuit breaker is open so the next attempt would be rejected
+func (e *execution) circuitOpen() bool {
+	return e.config.Breaker != nil && e.config.Breaker.State() == breaker.StateOpen
+}
+
+// withdraw reports whether the budget allows retrying
+func (e *execution) withdraw() bool {
+	return e.config.Budget == nil || e.config.Budget.withdraw()
+}
+
+// succeed deposits in the budget and notifies the success hook
+func (e *execution) succeed(ctx context.Context, number int) {
+	if e.config.Budget != nil {
+		e.config.Budget.deposit()
+	}
+
+	if e.config.OnSuccess != nil {
+		e.config.OnSuccess(ctx, Attempt{Number: number, Elapsed: e.elapsed()})
+	}
+}
+
+// giveUp notifies the give up hook and returns the error
+func (e *execution) giveUp(ctx context.Context, number int, err error) error {
+	if e.config.OnGiveUp != nil {
+		e.config.OnGiveUp(ctx, Attempt{Number: number, Err: err, Elapsed: e.elapsed()})
+	}
+
+	return err
+}
+
+// cause returns the error wrapped by the context errors
+func (e *execution) cause() error {
+	if e.config.AggregateErrors && len(e.errs) > 0 {
+		return e.errs
+	}
+
+	return e.lastErr
+}
+
+// exhausted returns the error when all the attempts failed
+func (e *execution) exhausted() error {
+	if e.config.AggregateErrors && len(e.errs) > 0 {
+		return app.NewErrorInternal(ErrorCodeExhausted, "all retry attempts failed").Wrap(e.errs)
+	}
+
+	return e.lastErr
+}
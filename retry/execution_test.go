@@ -0,0 +1,192 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Talento90/goliath/app"
+	"github.com/Talento90/goliath/backoff"
+	"github.com/Talento90/goliath/httperror"
+	"github.com/Talento90/goliath/sleep"
+)
+
+func TestExecuteHooksOnSuccess(t *testing.T) {
+	expectedErr := errors.New("connection refused")
+	counter := 0
+	var retries []Attempt
+	var success Attempt
+
+	task := func() (string, error) {
+		counter++
+
+		if counter == 3 {
+			return "My result", nil
+		}
+
+		return "", expectedErr
+	}
+
+	config := Config{
+		Times:   5,
+		Sleeper: sleep.NewRecorder(),
+		Backoff: backoff.Constant(time.Millisecond),
+		OnRetry: func(_ context.Context, attempt Attempt) bool {
+			retries = append(retries, attempt)
+			return true
+		},
+		OnSuccess: func(_ context.Context, attempt Attempt) {
+			success = attempt
+		},
+		OnGiveUp: func(context.Context, Attempt) {
+			t.Fatal("OnGiveUp should not be called")
+		},
+	}
+
+	result, err := Execute(config, task)
+
+	require.NoError(t, err)
+	require.Equal(t, "My result", result)
+	require.Len(t, retries, 2)
+	require.Equal(t, 1, retries[0].Number)
+	require.Equal(t, 2, retries[1].Number)
+	require.ErrorIs(t, retries[0].Err, expectedErr)
+	require.Equal(t, time.Millisecond, retries[0].Delay)
+	require.Equal(t, 3, success.Number)
+	require.NoError(t, success.Err)
+}
+
+func TestExecuteHooksOnGiveUp(t *testing.T) {
+	expectedErr := errors.New("connection refused")
+	var giveUp Attempt
+
+	task := func() (string, error) {
+		return "", expectedErr
+	}
+
+	config := Config{
+		Times:   3,
+		Sleeper: sleep.NewRecorder(),
+		OnGiveUp: func(_ context.Context, attempt Attempt) {
+			giveUp = attempt
+		},
+	}
+
+	_, err := Execute(config, task)
+
+	require.ErrorIs(t, err, expectedErr)
+	require.Equal(t, 3, giveUp.Number)
+	require.ErrorIs(t, giveUp.Err, expectedErr)
+}
+
+func TestExecuteOnRetryVeto(t *testing.T) {
+	expectedErr := errors.New("connection refused")
+	counter := 0
+	sleeper := sleep.NewRecorder()
+
+	task := func() (string, error) {
+		counter++
+		return "", expectedErr
+	}
+
+	config := Config{
+		Times:   5,
+		Sleeper: sleeper,
+		OnRetry: func(_ context.Context, attempt Attempt) bool {
+			return attempt.Number < 2
+		},
+	}
+
+	_, err := Execute(config, task)
+
+	require.ErrorIs(t, err, expectedErr)
+	require.Equal(t, 2, counter)
+	require.Len(t, sleeper.Calls(), 1)
+}
+
+func TestExecuteHooksReceiveContext(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "trace")
+	var value any
+
+	config := Config{
+		Times: 1,
+		OnSuccess: func(ctx context.Context, _ Attempt) {
+			value = ctx.Value(key{})
+		},
+	}
+
+	_, err := ExecuteContext(ctx, config, func(context.Context) (int, error) {
+		return 1, nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "trace", value)
+}
+
+func TestExecuteHonoursRetryAfterHint(t *testing.T) {
+	rateLimited := app.NewErrorRateLimited("rate_limit_exceeded", "Too many requests").SetRetryAfter(3 * time.Second)
+	unavailable := httperror.ProblemDetails{Status: http.StatusServiceUnavailable}
+	unavailable.SetRetryAfter(time.Hour)
+	errs := []error{rateLimited, fmt.Errorf("fetch: %w", unavailable), errors.New("connection refused"), errors.New("connection refused")}
+	counter := 0
+
+	task := func() (int, error) {
+		err := errs[counter]
+		counter++
+
+		return 0, err
+	}
+
+	sleeper := sleep.NewRecorder()
+	config := Config{
+		Times:         4,
+		Sleeper:       sleeper,
+		Backoff:       backoff.Constant(time.Millisecond),
+		MaxRetryAfter: time.Minute,
+		IsRetryable: func(error) bool {
+			return true
+		},
+	}
+
+	_, err := Execute(config, task)
+
+	require.Error(t, err)
+	require.Equal(t, []time.Duration{3 * time.Second, time.Minute, time.Millisecond}, sleeper.Calls())
+}
+
+func TestExecuteHonoursRetryAfterHintWithDefaultClassifier(t *testing.T) {
+	rateLimited := app.NewErrorRateLimited("rate_limit_exceeded", "Too many requests").SetRetryAfter(3 * time.Second)
+	errs := []error{
+		rateLimited,
+		// the hint is found behind an app error without retry after
+		app.NewErrorUnavailable("upstream_unavailable", "Upstream unavailable").Wrap(rateLimited),
+		errors.Join(errors.New("connection refused"), rateLimited),
+	}
+	counter := 0
+
+	task := func() (int, error) {
+		err := errs[counter]
+		counter++
+
+		return 0, err
+	}
+
+	sleeper := sleep.NewRecorder()
+	config := Config{
+		Times:   len(errs),
+		Sleeper: sleeper,
+		Backoff: backoff.Constant(time.Millisecond),
+	}
+
+	_, err := Execute(config, task)
+
+	require.ErrorIs(t, err, rateLimited)
+	require.Equal(t, len(errs), counter)
+	require.Equal(t, []time.Duration{3 * time.Second, 3 * time.Second}, sleeper.Calls())
+}
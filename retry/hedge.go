@@ -0,0 +1,132 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Talento90/goliath/app"
+)
+
+// hedgeResult is the outcome of a hedged attempt
+type hedgeResult[T any] struct {
+	number int
+	result T
+	err    error
+}
+
+// Hedge executes the task and fires a new attempt whenever the running attempts did not return
+// within the hedge delay calculated by the backoff strategy or as soon as an attempt fails.
+// The first successful result wins and the remaining attempts are cancelled through their context.
+// config.Times bounds the total number of attempts launched, so at most config.Times attempts run in parallel.
+// Errors are reported like ExecuteContext, including ErrorCodeInvalidTimes when config.Times is not positive.
+// The attempts are cancelled once config.MaxElapsedTime is exceeded and the execution fails with ErrorCodeTimeout.
+func Hedge[T any](ctx context.Context, config Config, task func(ctx context.Context) (T, error)) (T, error) {
+	var defaultResult T
+
+	exec := newExecution(config)
+	task = wrap(config, task)
+
+	if config.Times <= 0 {
+		return defaultResult, exec.giveUp(ctx, 0, invalidTimesError(config.Times))
+	}
+
+	if ctx.Err() != nil {
+		return defaultResult, exec.giveUp(ctx, 0, contextError(ctx, nil))
+	}
+
+	hedgeCtx, cancel := exec.withElapsedTime(ctx)
+	defer cancel()
+
+	results := make(chan hedgeResult[T], config.Times)
+	launched, pending := 0, 0
+	vetoed, denied, expired := false, false, false
+
+	launch := func() {
+		launched++
+		pending++
+
+		go func(number int) {
+			result, err := task(hedgeCtx)
+			results <- hedgeResult[T]{number: number, result: result, err: err}
+		}(launched)
+	}
+
+	launch()
+
+	timer := exec.clock.NewTimer(exec.strategy.Delay(launched))
+	defer timer.Stop()
+
+	// hedge launches a new attempt when allowed by the config and the hooks
+	hedge := func(number int, delay time.Duration) {
+		if vetoed || denied || launched >= config.Times {
+			return
+		}
+
+		if exec.exceeds(0) {
+			expired = true
+			return
+		}
+
+		if !exec.retry(ctx, number, delay) {
+			vetoed = true
+			return
+		}
+
+		if !exec.withdraw() {
+			denied = true
+			return
+		}
+
+		launch()
+		timer.Reset(exec.strategy.Delay(launched))
+	}
+
+	for pending > 0 {
+		select {
+		case <-ctx.Done():
+			return defaultResult, exec.giveUp(ctx, launched, contextError(ctx, exec.cause()))
+		case r := <-results:
+			pending--
+
+			if r.err == nil {
+				exec.succeed(ctx, r.number)
+				return r.result, nil
+			}
+
+			exec.fail(r.number, r.err)
+
+			if ctx.Err() != nil {
+				return defaultResult, exec.giveUp(ctx, launched, contextError(ctx, exec.cause()))
+			}
+
+			if errors.Is(hedgeCtx.Err(), context.DeadlineExceeded) {
+				return defaultResult, exec.giveUp(ctx, launched, elapsedTimeError(exec.cause()))
+			}
+
+			if !config.isRetryable(r.err) {
+				return defaultResult, exec.giveUp(ctx, r.number, exec.cause())
+			}
+
+			hedge(r.number, 0)
+		case <-timer.C():
+			hedge(launched, exec.strategy.Delay(launched))
+		}
+	}
+
+	if denied {
+		return defaultResult, exec.giveUp(ctx, launched, budgetExhausted(exec.cause()))
+	}
+
+	if expired {
+		return defaultResult, exec.giveUp(ctx, launched, elapsedTimeError(exec.cause()))
+	}
+
+	return defaultResult, exec.giveUp(ctx, launched, exec.exhausted())
+}
+
+// invalidTimesError returns the error when no attempt can be launched
+func invalidTimesError(times int) error {
+	return app.NewErrorInternal(ErrorCodeInvalidTimes, fmt.Sprintf("retry times must be positive, got %d", times))
+}
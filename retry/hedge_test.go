@@ -0,0 +1,228 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Talento90/goliath/app"
+	"github.com/Talento90/goliath/backoff"
+	"github.com/Talento90/goliath/clock"
+)
+
+func TestHedgeFirstAttemptSucceeds(t *testing.T) {
+	var calls atomic.Int32
+
+	task := func(context.Context) (string, error) {
+		calls.Add(1)
+		return "My result", nil
+	}
+
+	result, err := Hedge(context.Background(), Config{Times: 3, Backoff: backoff.Constant(time.Hour)}, task)
+
+	require.NoError(t, err)
+	require.Equal(t, "My result", result)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestHedgeInvalidTimes(t *testing.T) {
+	var calls atomic.Int32
+
+	_, err := Hedge(context.Background(), Config{Times: 0}, func(context.Context) (string, error) {
+		calls.Add(1)
+		return "My result", nil
+	})
+
+	var appErr *app.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, ErrorCodeInvalidTimes, appErr.Code())
+	require.Equal(t, int32(0), calls.Load())
+}
+
+func TestHedgeFiresAfterDelayAndCancelsSlowAttempt(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var calls atomic.Int32
+	cancelled := make(chan struct{})
+
+	task := func(ctx context.Context) (string, error) {
+		if calls.Add(1) == 1 {
+			<-ctx.Done()
+			close(cancelled)
+
+			return "", ctx.Err()
+		}
+
+		return "hedged result", nil
+	}
+
+	config := Config{
+		Times:   3,
+		Backoff: backoff.Constant(100 * time.Millisecond),
+		Clock:   clk,
+	}
+
+	done := make(chan struct{})
+	var result string
+	var err error
+
+	go func() {
+		result, err = Hedge(context.Background(), config, task)
+		close(done)
+	}()
+
+	clk.BlockUntil(1)
+	clk.Advance(100 * time.Millisecond)
+	<-done
+
+	require.NoError(t, err)
+	require.Equal(t, "hedged result", result)
+	require.Equal(t, int32(2), calls.Load())
+	<-cancelled
+}
+
+func TestHedgeLaunchesNextAttemptOnFailure(t *testing.T) {
+	expectedErr := errors.New("connection refused")
+	var calls atomic.Int32
+	var retries []Attempt
+
+	task := func(context.Context) (string, error) {
+		if calls.Add(1) < 3 {
+			return "", expectedErr
+		}
+
+		return "My result", nil
+	}
+
+	config := Config{
+		Times:   3,
+		Backoff: backoff.Constant(time.Hour),
+		OnRetry: func(_ context.Context, attempt Attempt) bool {
+			retries = append(retries, attempt)
+			return true
+		},
+	}
+
+	result, err := Hedge(context.Background(), config, task)
+
+	require.NoError(t, err)
+	require.Equal(t, "My result", result)
+	require.Len(t, retries, 2)
+	require.ErrorIs(t, retries[0].Err, expectedErr)
+}
+
+func TestHedgeAllAttemptsFail(t *testing.T) {
+	expectedErr := errors.New("connection refused")
+	var calls atomic.Int32
+
+	task := func(context.Context) (int, error) {
+		calls.Add(1)
+		return 0, expectedErr
+	}
+
+	config := Config{
+		Times:           3,
+		Backoff:         backoff.Constant(time.Hour),
+		AggregateErrors: true,
+	}
+
+	_, err := Hedge(context.Background(), config, task)
+
+	var appErr *app.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, ErrorCodeExhausted, appErr.Code())
+	require.ErrorIs(t, err, expectedErr)
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestHedgeStopsOnNonRetryableError(t *testing.T) {
+	expectedErr := app.NewErrorNotFound("user_not_found", "User not found")
+	var calls atomic.Int32
+
+	task := func(context.Context) (int, error) {
+		calls.Add(1)
+		return 0, expectedErr
+	}
+
+	_, err := Hedge(context.Background(), Config{Times: 3, Backoff: backoff.Constant(time.Hour)}, task)
+
+	require.ErrorIs(t, err, expectedErr)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestHedgeContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	task := func(ctx context.Context) (int, error) {
+		cancel()
+		<-ctx.Done()
+
+		return 0, ctx.Err()
+	}
+
+	_, err := Hedge(ctx, Config{Times: 3, Backoff: backoff.Constant(time.Hour)}, task)
+
+	var appErr *app.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, app.ErrorCancelled, appErr.Type())
+}
+
+func TestHedgeMaxElapsedTimeExceeded(t *testing.T) {
+	expectedErr := errors.New("connection refused")
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var calls atomic.Int32
+
+	task := func(context.Context) (int, error) {
+		calls.Add(1)
+		clk.Advance(2 * time.Second)
+
+		return 0, expectedErr
+	}
+
+	config := Config{
+		Times:          3,
+		Backoff:        backoff.Constant(time.Hour),
+		Clock:          clk,
+		MaxElapsedTime: time.Second,
+	}
+
+	_, err := Hedge(context.Background(), config, task)
+
+	var appErr *app.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, ErrorCodeTimeout, appErr.Code())
+	require.ErrorIs(t, err, expectedErr)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestHedgeAggregateErrorsWhenNotRetryable(t *testing.T) {
+	connectionErr := errors.New("connection refused")
+	notFoundErr := app.NewErrorNotFound("user_not_found", "User not found")
+	var calls atomic.Int32
+
+	task := func(context.Context) (int, error) {
+		if calls.Add(1) == 1 {
+			return 0, connectionErr
+		}
+
+		return 0, notFoundErr
+	}
+
+	config := Config{
+		Times:           3,
+		Backoff:         backoff.Constant(time.Hour),
+		AggregateErrors: true,
+	}
+
+	_, err := Hedge(context.Background(), config, task)
+
+	var attemptErrs Errors
+	require.ErrorAs(t, err, &attemptErrs)
+	require.Len(t, attemptErrs, 2)
+	require.ErrorIs(t, err, connectionErr)
+	require.ErrorIs(t, err, notFoundErr)
+	require.Equal(t, int32(2), calls.Load())
+}
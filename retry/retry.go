@@ -1,60 +1,249 @@
 package retry
 
 import (
+	"context"
+	"errors"
 	"time"
 
+	"github.com/Talento90/goliath/app"
+	"github.com/Talento90/goliath/backoff"
+	"github.com/Talento90/goliath/breaker"
+	"github.com/Talento90/goliath/clock"
 	"github.com/Talento90/goliath/sleep"
 )
 
+const (
+	// ErrorCodeCancelled is the error code returned when the context is cancelled
+	ErrorCodeCancelled = "retry_cancelled"
+	// ErrorCodeTimeout is the error code returned when the context deadline or the max elapsed time is exceeded
+	ErrorCodeTimeout = "retry_timeout"
+	// ErrorCodeAttemptTimeout is the error code returned when a single attempt exceeds the per attempt timeout
+	ErrorCodeAttemptTimeout = "retry_attempt_timeout"
+	// ErrorCodeInvalidTimes is the error code returned by Hedge when the number of attempts is not positive
+	ErrorCodeInvalidTimes = "retry_invalid_times"
+)
+
 // Config retry mechanism
 type Config struct {
 	// Number of retries to be applied
 	Times int
-	// ExponentialBackoff function that calculates the retry delay
+	// Backoff strategy that calculates the retry delay, it is shared by the concurrent executions of the config
+	Backoff backoff.Backoff
+	// ExponentialBackoff function that calculates the retry delay in milliseconds
+	//
+	// Deprecated: use Backoff instead, ExponentialBackoff is only used when Backoff is nil.
 	ExponentialBackoff func(retryCount int) time.Duration
 	// Sleeper pauses the execution of the current go routine for x milliseconds
 	Sleeper sleep.Sleeper
+	// MaxElapsedTime bounds the total time of the execution measured by the Clock including the running attempt,
+	// whose context is cancelled when exceeded, zero means no limit
+	MaxElapsedTime time.Duration
+	// PerAttemptTimeout cancels the context of each attempt when exceeded, zero means no timeout
+	PerAttemptTimeout time.Duration
+	// MaxRetryAfter caps the delay requested by errors implementing Hint, zero means no cap
+	MaxRetryAfter time.Duration
+	// IsRetryable classifies the task error, DefaultIsRetryable is used when nil.
+	// Errors wrapped with Permanent are never retried.
+	IsRetryable func(err error) bool
+	// AggregateErrors returns every attempt error wrapped in an app.Error with the code ErrorCodeExhausted
+	// instead of the last task error, a non-retryable error is returned with the previous ones as Errors
+	AggregateErrors bool
+	// Clock registers when each attempt failed and measures the elapsed time, a UTC clock is used when nil
+	Clock clock.Clock
+	// Budget shared across executions denies retries once it is exhausted
+	Budget *Budget
+	// Breaker protects every attempt, the retry stops without waiting as soon as the circuit is open
+	Breaker *breaker.Breaker
+	// OnRetry is called before waiting for the next attempt, returning false stops retrying
+	OnRetry func(ctx context.Context, attempt Attempt) bool
+	// OnGiveUp is called when the task failed and no more attempts will be executed
+	OnGiveUp func(ctx context.Context, attempt Attempt)
+	// OnSuccess is called when the task succeeds
+	OnSuccess func(ctx context.Context, attempt Attempt)
+}
+
+// Hint is implemented by errors that carry the delay requested by the server before retrying,
+// like app.Error and httperror.ProblemDetails. The hint replaces the backoff delay when it is positive.
+type Hint interface {
+	RetryAfter() time.Duration
+}
+
+// Attempt describes a task execution
+type Attempt struct {
+	// Number of the attempt starting at 1
+	Number int
+	// Err returned by the attempt, nil when the task succeeds
+	Err error
+	// Delay to wait before the next attempt
+	Delay time.Duration
+	// Elapsed time since the first attempt started
+	Elapsed time.Duration
 }
 
 func NewConfig(retryCount int) Config {
 	return Config{
-		Sleeper:            sleep.New(),
-		Times:              retryCount,
-		ExponentialBackoff: defaultExponentialBackoff,
+		Sleeper:       sleep.New(),
+		Times:         retryCount,
+		Clock:         clock.NewUtcClock(),
+		MaxRetryAfter: time.Minute,
 	}
 }
 
-func defaultExponentialBackoff(retryCount int) time.Duration {
-	return time.Duration(retryCount * 100)
+var defaultBackoff = backoff.Exponential(100*time.Millisecond, 10*time.Second)
+
+// backoff returns the configured backoff strategy, the deprecated ExponentialBackoff
+// or an exponential backoff from 100ms up to 10s
+func (c Config) backoff() backoff.Backoff {
+	if c.Backoff != nil {
+		return c.Backoff
+	}
+
+	if c.ExponentialBackoff != nil {
+		return backoff.Func(func(retryCount int) time.Duration {
+			return c.ExponentialBackoff(retryCount) * time.Millisecond
+		})
+	}
+
+	return defaultBackoff
 }
 
 // Execute the task and retries when the task returns an error
 func Execute[T any](config Config, task func() (T, error)) (T, error) {
-	var retryErr error
+	return ExecuteContext(context.Background(), config, func(context.Context) (T, error) {
+		return task()
+	})
+}
+
+// ExecuteContext executes the task and retries when the task returns an error.
+// It returns the zero value and a nil error without running the task when config.Times is not positive.
+// The execution stops as soon as the context is done, returning an app.Error of type
+// app.ErrorCancelled or app.ErrorTimeout that wraps the last task error.
+func ExecuteContext[T any](ctx context.Context, config Config, task func(ctx context.Context) (T, error)) (T, error) {
 	var defaultResult T
 
-	for i := 0; i < config.Times; i++ {
-		result, err := task()
+	exec := newExecution(config)
+	task = wrap(config, task)
+
+	if config.Times <= 0 {
+		return defaultResult, nil
+	}
+
+	for i := 1; i <= config.Times; i++ {
+		if ctx.Err() != nil {
+			return defaultResult, exec.giveUp(ctx, i-1, contextError(ctx, exec.cause()))
+		}
+
+		attemptCtx, cancel := exec.withElapsedTime(ctx)
+		result, err := task(attemptCtx)
+		cancel()
 
 		if err == nil {
+			exec.succeed(ctx, i)
 			return result, nil
 		}
 
-		if i == config.Times-1 {
-			retryErr = err
+		exec.fail(i, err)
+
+		if ctx.Err() != nil {
+			return defaultResult, exec.giveUp(ctx, i, contextError(ctx, exec.cause()))
+		}
+
+		if exec.expired(attemptCtx) {
+			return defaultResult, exec.giveUp(ctx, i, elapsedTimeError(exec.cause()))
+		}
+
+		if !config.isRetryable(err) {
+			return defaultResult, exec.giveUp(ctx, i, exec.cause())
+		}
+
+		if i == config.Times {
+			break
+		}
+
+		if exec.circuitOpen() {
+			return defaultResult, exec.giveUp(ctx, i, circuitOpenError(exec.cause()))
+		}
+
+		delay := exec.delay(i, err)
+
+		if exec.exceeds(delay) {
+			return defaultResult, exec.giveUp(ctx, i, elapsedTimeError(exec.cause()))
+		}
+
+		if !exec.retry(ctx, i, delay) {
 			break
 		}
 
-		var delay time.Duration
+		// the budget is only spent by retries that are going to happen
+		if !exec.withdraw() {
+			return defaultResult, exec.giveUp(ctx, i, budgetExhausted(exec.cause()))
+		}
+
+		if err := sleep.WithContext(ctx, exec.sleeper, delay); err != nil {
+			return defaultResult, exec.giveUp(ctx, i, contextError(ctx, exec.cause()))
+		}
+	}
+
+	return defaultResult, exec.giveUp(ctx, len(exec.errs), exec.exhausted())
+}
+
+// wrap decorates the task with the per attempt timeout and the circuit breaker
+func wrap[T any](config Config, task func(ctx context.Context) (T, error)) func(ctx context.Context) (T, error) {
+	if config.PerAttemptTimeout > 0 {
+		task = timeout(config.PerAttemptTimeout, task)
+	}
+
+	if config.Breaker != nil {
+		task = protect(config.Breaker, task)
+	}
+
+	return task
+}
+
+// timeout cancels the context of the task when the timeout is exceeded
+func timeout[T any](d time.Duration, task func(ctx context.Context) (T, error)) func(ctx context.Context) (T, error) {
+	return func(ctx context.Context) (T, error) {
+		attemptCtx, cancel := context.WithTimeout(ctx, d)
+		defer cancel()
+
+		result, err := task(attemptCtx)
 
-		if config.ExponentialBackoff != nil {
-			delay = config.ExponentialBackoff(i + 1)
-		} else {
-			delay = defaultExponentialBackoff(i + 1)
+		if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+			return result, app.NewErrorTimeout(ErrorCodeAttemptTimeout, "retry attempt timeout exceeded").Wrap(err)
 		}
 
-		config.Sleeper.Sleep(delay * time.Millisecond)
+		return result, err
+	}
+}
+
+// protect executes the task through the circuit breaker
+func protect[T any](b *breaker.Breaker, task func(ctx context.Context) (T, error)) func(ctx context.Context) (T, error) {
+	return func(ctx context.Context) (T, error) {
+		return breaker.Execute(ctx, b, task)
+	}
+}
+
+// circuitOpenError returns the error when the circuit opened after the last attempt
+func circuitOpenError(cause error) error {
+	return app.NewErrorUnavailable(breaker.ErrorCodeOpen, "retry stopped, circuit breaker is open").Wrap(cause)
+}
+
+// elapsedTimeError returns the error when the max elapsed time is exceeded
+func elapsedTimeError(cause error) error {
+	return app.NewErrorTimeout(ErrorCodeTimeout, "retry max elapsed time exceeded").Wrap(cause)
+}
+
+// contextError converts the context error into an app.Error wrapping the last task error
+func contextError(ctx context.Context, lastErr error) error {
+	cause := lastErr
+
+	if cause == nil {
+		cause = ctx.Err()
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return app.NewErrorTimeout(ErrorCodeTimeout, "retry deadline exceeded").Wrap(cause)
 	}
 
-	return defaultResult, retryErr
+	return app.NewErrorCancelled(ErrorCodeCancelled, "retry cancelled").Wrap(cause)
 }
@@ -1,37 +1,35 @@
 package retry
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
-)
-
-type mockSleep struct {
-	Counter int
-}
 
-func (m *mockSleep) Sleep(time.Duration) {
-	m.Counter = m.Counter + 1
-}
+	"github.com/Talento90/goliath/app"
+	"github.com/Talento90/goliath/backoff"
+	"github.com/Talento90/goliath/clock"
+	"github.com/Talento90/goliath/sleep"
+)
 
 func TestExecuteSuccessNoRetries(t *testing.T) {
 	task := func() (string, error) {
 		return "my result", nil
 	}
 
-	mockSleep := &mockSleep{}
+	sleeper := sleep.NewRecorder()
 	config := Config{
 		Times:   5,
-		Sleeper: mockSleep,
+		Sleeper: sleeper,
 	}
 
 	result, err := Execute(config, task)
 
 	require.NoError(t, err)
 	require.Equal(t, "my result", result)
-	require.Equal(t, 0, mockSleep.Counter)
+	require.Empty(t, sleeper.Calls())
 }
 
 func TestExecuteSuccessNoRetriesWithDefaultConstructor(t *testing.T) {
@@ -59,18 +57,18 @@ func TestExecuteSuccessAfterRetries(t *testing.T) {
 		return "", expectedErr
 	}
 
-	mockSleep := &mockSleep{}
+	sleeper := sleep.NewRecorder()
 
 	config := Config{
 		Times:   5,
-		Sleeper: mockSleep,
+		Sleeper: sleeper,
 	}
 
 	result, err := Execute(config, task)
 
 	require.NoError(t, err)
 	require.Equal(t, "My result", result)
-	require.Equal(t, 2, mockSleep.Counter)
+	require.Len(t, sleeper.Calls(), 2)
 }
 
 func TestExecuteSuccessAfterRetriesWithCustomExponentialBackoff(t *testing.T) {
@@ -87,11 +85,11 @@ func TestExecuteSuccessAfterRetriesWithCustomExponentialBackoff(t *testing.T) {
 		return "", expectedErr
 	}
 
-	mockSleep := &mockSleep{}
+	sleeper := sleep.NewRecorder()
 
 	config := Config{
 		Times:   5,
-		Sleeper: mockSleep,
+		Sleeper: sleeper,
 		ExponentialBackoff: func(retryCount int) time.Duration {
 			return time.Duration(retryCount)
 		},
@@ -101,7 +99,61 @@ func TestExecuteSuccessAfterRetriesWithCustomExponentialBackoff(t *testing.T) {
 
 	require.NoError(t, err)
 	require.Equal(t, "My result", result)
-	require.Equal(t, 2, mockSleep.Counter)
+	require.Len(t, sleeper.Calls(), 2)
+	require.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond}, sleeper.Calls())
+}
+
+func TestExecuteWithBackoffStrategy(t *testing.T) {
+	task := func() (string, error) {
+		return "", errors.New("Couldn't fetch to the database")
+	}
+
+	sleeper := sleep.NewRecorder()
+
+	config := Config{
+		Times:   4,
+		Sleeper: sleeper,
+		Backoff: backoff.Exponential(100*time.Millisecond, 300*time.Millisecond),
+	}
+
+	_, err := Execute(config, task)
+
+	require.Error(t, err)
+	require.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}, sleeper.Calls())
+}
+
+func TestExecuteDefaultBackoffIsExponential(t *testing.T) {
+	task := func() (string, error) {
+		return "", errors.New("Couldn't fetch to the database")
+	}
+
+	sleeper := sleep.NewRecorder()
+
+	config := Config{
+		Times:   4,
+		Sleeper: sleeper,
+	}
+
+	_, err := Execute(config, task)
+
+	require.Error(t, err)
+	require.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}, sleeper.Calls())
+}
+
+func TestExecuteExponentialBackoffWithDefaultConstructor(t *testing.T) {
+	sleeper := sleep.NewRecorder()
+	config := NewConfig(3)
+	config.Sleeper = sleeper
+	config.ExponentialBackoff = func(int) time.Duration {
+		return 7
+	}
+
+	_, err := Execute(config, func() (int, error) {
+		return 0, errors.New("Couldn't fetch to the database")
+	})
+
+	require.Error(t, err)
+	require.Equal(t, []time.Duration{7 * time.Millisecond, 7 * time.Millisecond}, sleeper.Calls())
 }
 
 func TestExecuteAlwaysError(t *testing.T) {
@@ -111,16 +163,224 @@ func TestExecuteAlwaysError(t *testing.T) {
 		return 0, expectedErr
 	}
 
-	mockSleep := &mockSleep{}
+	sleeper := sleep.NewRecorder()
 
 	config := Config{
 		Times:   3,
-		Sleeper: mockSleep,
+		Sleeper: sleeper,
 	}
 
 	result, err := Execute(config, task)
 
 	require.ErrorIs(t, expectedErr, err)
 	require.Equal(t, 0, result)
-	require.Equal(t, 2, mockSleep.Counter)
+	require.Len(t, sleeper.Calls(), 2)
+}
+
+func TestExecuteNonPositiveTimes(t *testing.T) {
+	for _, times := range []int{0, -1} {
+		called := false
+
+		_, err := Execute(Config{Times: times}, func() (int, error) {
+			called = true
+			return 1, nil
+		})
+
+		require.NoError(t, err)
+		require.False(t, called)
+	}
+}
+
+func TestExecuteContextCancelledWhileSleeping(t *testing.T) {
+	expectedErr := errors.New("Couldn't connect to the database")
+	ctx, cancel := context.WithCancel(context.Background())
+	counter := 0
+
+	task := func(context.Context) (int, error) {
+		counter++
+		cancel()
+
+		return 0, expectedErr
+	}
+
+	config := Config{
+		Times: 3,
+		ExponentialBackoff: func(int) time.Duration {
+			return time.Hour
+		},
+	}
+
+	result, err := ExecuteContext(ctx, config, task)
+
+	var appErr *app.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, app.ErrorCancelled, appErr.Type())
+	require.Equal(t, ErrorCodeCancelled, appErr.Code())
+	require.ErrorIs(t, err, expectedErr)
+	require.Equal(t, 0, result)
+	require.Equal(t, 1, counter)
+}
+
+func TestExecuteContextDeadlineExceeded(t *testing.T) {
+	expectedErr := errors.New("Couldn't connect to the database")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	task := func(context.Context) (int, error) {
+		return 0, expectedErr
+	}
+
+	config := Config{
+		Times: 3,
+		ExponentialBackoff: func(int) time.Duration {
+			return time.Hour
+		},
+	}
+
+	_, err := ExecuteContext(ctx, config, task)
+
+	var appErr *app.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, app.ErrorTimeout, appErr.Type())
+	require.Equal(t, ErrorCodeTimeout, appErr.Code())
+	require.ErrorIs(t, err, expectedErr)
+}
+
+func TestExecuteContextAlreadyCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	counter := 0
+
+	task := func(context.Context) (int, error) {
+		counter++
+		return 1, nil
+	}
+
+	_, err := ExecuteContext(ctx, NewConfig(3), task)
+
+	var appErr *app.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, app.ErrorCancelled, appErr.Type())
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 0, counter)
+}
+
+func TestExecuteMaxElapsedTime(t *testing.T) {
+	expectedErr := errors.New("Couldn't connect to the database")
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	counter := 0
+
+	task := func() (int, error) {
+		counter++
+		clk.Advance(time.Second)
+
+		return 0, expectedErr
+	}
+
+	sleeper := sleep.NewFake(clk)
+	config := Config{
+		Times:          10,
+		Sleeper:        sleeper,
+		Clock:          clk,
+		Backoff:        backoff.Constant(2 * time.Second),
+		MaxElapsedTime: 7 * time.Second,
+	}
+
+	_, err := Execute(config, task)
+
+	var appErr *app.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, app.ErrorTimeout, appErr.Type())
+	require.Equal(t, ErrorCodeTimeout, appErr.Code())
+	require.ErrorIs(t, err, expectedErr)
+	require.Equal(t, 3, counter)
+	require.Equal(t, 4*time.Second, sleeper.TotalSlept())
+}
+
+func TestExecuteMaxElapsedTimeCancelsRunningAttempt(t *testing.T) {
+	counter := 0
+
+	task := func(ctx context.Context) (int, error) {
+		counter++
+		<-ctx.Done()
+
+		return 0, ctx.Err()
+	}
+
+	config := Config{
+		Times:          3,
+		Sleeper:        sleep.NewRecorder(),
+		MaxElapsedTime: time.Millisecond,
+	}
+
+	_, err := ExecuteContext(context.Background(), config, task)
+
+	var appErr *app.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, ErrorCodeTimeout, appErr.Code())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, 1, counter)
+}
+
+func TestExecuteMaxElapsedTimeExceededByLastAttempt(t *testing.T) {
+	expectedErr := errors.New("Couldn't connect to the database")
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	task := func() (int, error) {
+		clk.Advance(3 * time.Second)
+		return 0, expectedErr
+	}
+
+	config := Config{
+		Times:          2,
+		Sleeper:        sleep.NewFake(clk),
+		Clock:          clk,
+		Backoff:        backoff.Constant(time.Second),
+		MaxElapsedTime: 5 * time.Second,
+	}
+
+	_, err := Execute(config, task)
+
+	var appErr *app.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, app.ErrorTimeout, appErr.Type())
+	require.Equal(t, ErrorCodeTimeout, appErr.Code())
+	require.ErrorIs(t, err, expectedErr)
+}
+
+func TestExecuteContextPerAttemptTimeout(t *testing.T) {
+	counter := 0
+
+	task := func(ctx context.Context) (string, error) {
+		counter++
+
+		if counter == 1 {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+
+		return "My result", nil
+	}
+
+	var retried Attempt
+	config := Config{
+		Times:             3,
+		Sleeper:           sleep.NewRecorder(),
+		PerAttemptTimeout: time.Millisecond,
+		OnRetry: func(_ context.Context, attempt Attempt) bool {
+			retried = attempt
+			return true
+		},
+	}
+
+	result, err := ExecuteContext(context.Background(), config, task)
+
+	require.NoError(t, err)
+	require.Equal(t, "My result", result)
+	require.Equal(t, 2, counter)
+
+	var appErr *app.Error
+	require.ErrorAs(t, retried.Err, &appErr)
+	require.Equal(t, ErrorCodeAttemptTimeout, appErr.Code())
+	require.ErrorIs(t, retried.Err, context.DeadlineExceeded)
 }
@@ -0,0 +1,73 @@
+package sleep
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Talento90/goliath/clock"
+)
+
+// Recorder is a Sleeper for tests that records every requested duration without sleeping
+type Recorder struct {
+	mu    sync.Mutex
+	calls []time.Duration
+	clock *clock.Fake
+}
+
+// NewRecorder returns a sleeper that records every requested duration without sleeping
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// NewFake returns a sleeper that records every requested duration and advances the fake clock instead of sleeping
+func NewFake(c *clock.Fake) *Recorder {
+	return &Recorder{clock: c}
+}
+
+// Sleep records the duration and advances the fake clock
+func (r *Recorder) Sleep(d time.Duration) {
+	r.mu.Lock()
+	r.calls = append(r.calls, d)
+	r.mu.Unlock()
+
+	if r.clock != nil {
+		r.clock.Advance(d)
+	}
+}
+
+// SleepContext records the duration and advances the fake clock unless the context is done
+func (r *Recorder) SleepContext(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Sleep(d)
+
+	return nil
+}
+
+// Calls returns every requested duration
+func (r *Recorder) Calls() []time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	calls := make([]time.Duration, len(r.calls))
+	copy(calls, r.calls)
+
+	return calls
+}
+
+// TotalSlept returns the sum of every requested duration
+func (r *Recorder) TotalSlept() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var total time.Duration
+
+	for _, d := range r.calls {
+		total += d
+	}
+
+	return total
+}
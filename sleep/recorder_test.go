@@ -0,0 +1,43 @@
+package sleep
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Talento90/goliath/clock"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder()
+
+	recorder.Sleep(time.Second)
+	require.NoError(t, recorder.SleepContext(context.Background(), time.Minute))
+
+	require.Equal(t, []time.Duration{time.Second, time.Minute}, recorder.Calls())
+	require.Equal(t, time.Minute+time.Second, recorder.TotalSlept())
+}
+
+func TestRecorderCancelledContext(t *testing.T) {
+	recorder := NewRecorder()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := WithContext(ctx, recorder, time.Second)
+
+	require.ErrorIs(t, err, context.Canceled)
+	require.Empty(t, recorder.Calls())
+}
+
+func TestFakeAdvancesClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := clock.NewFake(start)
+	sleeper := NewFake(c)
+
+	sleeper.Sleep(time.Hour)
+
+	require.Equal(t, start.Add(time.Hour), c.Now())
+	require.Equal(t, time.Hour, sleeper.TotalSlept())
+}
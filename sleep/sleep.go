@@ -1,16 +1,25 @@
 package sleep
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // Sleeper pauses the current go routine
 type Sleeper interface {
 	Sleep(time.Duration)
 }
 
+// ContextSleeper pauses the current go routine until the duration elapses or the context is done
+type ContextSleeper interface {
+	Sleeper
+	SleepContext(ctx context.Context, d time.Duration) error
+}
+
 type sleeper struct{}
 
 // New returns a new sleep
-func New() Sleeper {
+func New() ContextSleeper {
 	return sleeper{}
 }
 
@@ -18,3 +27,33 @@ func New() Sleeper {
 func (sleeper) Sleep(d time.Duration) {
 	time.Sleep(d)
 }
+
+// SleepContext pauses the execution of the current go routine until the duration elapses or the context is done.
+// It returns the context error when the sleep was interrupted.
+func (sleeper) SleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// WithContext pauses the execution using the sleeper honouring the context cancellation.
+// Sleepers that do not implement ContextSleeper are not interrupted, the context is checked before and after sleeping.
+func WithContext(ctx context.Context, s Sleeper, d time.Duration) error {
+	if cs, ok := s.(ContextSleeper); ok {
+		return cs.SleepContext(ctx, d)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.Sleep(d)
+
+	return ctx.Err()
+}
@@ -1,7 +1,9 @@
 package sleep
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -13,3 +15,37 @@ func TestSleep(t *testing.T) {
 
 	require.NotNil(t, sleeper)
 }
+
+func TestSleepContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := New().SleepContext(ctx, time.Hour)
+
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestWithContextSleeper(t *testing.T) {
+	err := WithContext(context.Background(), New(), time.Millisecond)
+
+	require.NoError(t, err)
+}
+
+type mockSleep struct {
+	Counter int
+}
+
+func (m *mockSleep) Sleep(time.Duration) {
+	m.Counter++
+}
+
+func TestWithContextPlainSleeperCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	mockSleep := &mockSleep{}
+
+	err := WithContext(ctx, mockSleep, time.Hour)
+
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 0, mockSleep.Counter)
+}